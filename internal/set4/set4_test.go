@@ -0,0 +1,138 @@
+package set4
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/attack"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+	"github.com/jonathanlamela/go-cryptopals/pkg/sha1x"
+)
+
+func TestChallenge28(t *testing.T) {
+	o := or.NewOracle28()
+	msg := []byte("the quick brown fox")
+	mac := o.MAC(msg)
+
+	// The MAC is plain SHA-1 over key||msg
+	want := sha1.Sum(append(append([]byte(nil), o.Key...), msg...))
+	if !bytes.Equal(mac, want[:]) {
+		t.Fatalf("MAC mismatch: %x != %x", mac, want)
+	}
+	if !o.Verify(msg, mac) {
+		t.Fatal("valid MAC rejected")
+	}
+
+	// Tampering with the message or guessing without the key must fail
+	if o.Verify([]byte("the quick brown fix"), mac) {
+		t.Fatal("tampered message accepted")
+	}
+	guess := sha1x.Sum(msg)
+	if o.Verify(msg, guess[:]) {
+		t.Fatal("MAC forged without the key")
+	}
+}
+
+func TestChallenge29(t *testing.T) {
+	o := or.NewOracle28()
+	forgedMsg, forgedMAC, err := attack.ForgeSHA1Admin(o, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.IsAdmin(forgedMsg, forgedMAC) {
+		t.Fatal("forged admin message rejected")
+	}
+	if !bytes.HasSuffix(forgedMsg, []byte(";admin=true")) {
+		t.Fatalf("unexpected forged message: %q", forgedMsg)
+	}
+}
+
+func TestChallenge30(t *testing.T) {
+	o := or.NewOracle30()
+	forgedMsg, forgedMAC, err := attack.ForgeMD4Admin(o, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.IsAdmin(forgedMsg, forgedMAC) {
+		t.Fatal("forged admin message rejected")
+	}
+	if !bytes.HasSuffix(forgedMsg, []byte(";admin=true")) {
+		t.Fatalf("unexpected forged message: %q", forgedMsg)
+	}
+}
+
+func TestChallenge31(t *testing.T) {
+	// Limitation: this only recovers a 3-byte signature. A full 20-byte
+	// recovery at 5ms takes minutes; three bytes exercise the same timing
+	// steps and the status-code finish in a few seconds, and
+	// TestChallenge31LongSignature covers eight at a shorter delay.
+	// The test measures wall-clock time, so a heavily loaded machine can
+	// still make it fail.
+	o, err := or.NewOracle31(5*time.Millisecond, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(o)
+	defer srv.Close()
+
+	file := "foo"
+	sig, err := attack.RecoverHMACByTiming(srv.Client(), srv.URL+"/test", file, attack.TimingOptions{SigLen: o.SigLen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := o.HMAC([]byte(file)); !bytes.Equal(sig, want) {
+		t.Fatalf("recovered %x, want %x", sig, want)
+	}
+}
+
+func TestChallenge31LongSignature(t *testing.T) {
+	// Eight bytes at a 1ms delay: six timed positions past the first, where
+	// jitter grows with every byte compared and wrong winners have to be
+	// caught and backtracked over. Jitter this deep needs three first-pass
+	// timings per value. It takes about a minute of mostly sleeping, so
+	// -short skips it; all 20 bytes would take several.
+	if testing.Short() {
+		t.Skip("long signature recovery takes about a minute; run without -short")
+	}
+	o, err := or.NewOracle31(time.Millisecond, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(o)
+	defer srv.Close()
+
+	file := "foo"
+	opts := attack.TimingOptions{SigLen: o.SigLen, FirstPass: 3}
+	sig, err := attack.RecoverHMACByTiming(srv.Client(), srv.URL+"/test", file, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := o.HMAC([]byte(file)); !bytes.Equal(sig, want) {
+		t.Fatalf("recovered %x, want %x", sig, want)
+	}
+}
+
+func TestChallenge32(t *testing.T) {
+	// Sub-millisecond leak: more samples and a trimmed mean to beat the
+	// jitter. The same limitation as Challenge 31 applies: 3 bytes only, and
+	// the result depends on wall-clock timing.
+	o, err := or.NewOracle31(500*time.Microsecond, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(o)
+	defer srv.Close()
+
+	file := "foo"
+	opts := attack.TimingOptions{SigLen: o.SigLen, FirstPass: 3, Samples: 15, Stat: attack.TrimmedMean(0.2)}
+	sig, err := attack.RecoverHMACByTiming(srv.Client(), srv.URL+"/test", file, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := o.HMAC([]byte(file)); !bytes.Equal(sig, want) {
+		t.Fatalf("recovered %x, want %x", sig, want)
+	}
+}
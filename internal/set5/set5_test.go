@@ -0,0 +1,227 @@
+package set5
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/attack"
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+	"github.com/jonathanlamela/go-cryptopals/pkg/srp"
+)
+
+func TestChallenge33(t *testing.T) {
+	// Toy parameters first: p=37, g=5
+	small := dh.NewGroup(big.NewInt(37), big.NewInt(5))
+	a, err := small.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := small.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.SharedSecret(b.Public).Cmp(b.SharedSecret(a.Public)) != 0 {
+		t.Fatal("toy shared secrets differ")
+	}
+
+	// Then the real NIST group, with the secret turned into an AES key
+	g := dh.NISTGroup()
+	a, err = g.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = g.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyA := dh.SessionKey(a.SharedSecret(b.Public))
+	keyB := dh.SessionKey(b.SharedSecret(a.Public))
+
+	iv := cu.RandomBytes(16)
+	msg := []byte("Diffie-Hellman works")
+	ct, err := cu.CryptoBytes(msg).SSLCBCEncrypt(keyA, iv, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := cu.CryptoBytes(ct).SSLCBCDecrypt(keyB, iv, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pt) != string(msg) {
+		t.Fatalf("decryption mismatch: %q", pt)
+	}
+}
+
+// runEchoWithMITM runs one echo session A <-> M <-> B and returns A's echo and M.
+func runEchoWithMITM(t *testing.T, inj attack.Injection, msg []byte) ([]byte, *attack.DHMITM) {
+	t.Helper()
+	a, mA := dh.Pipe()
+	mB, b := dh.Pipe()
+	m := &attack.DHMITM{Injection: inj}
+
+	errc := make(chan error, 2)
+	go func() { errc <- dh.Bob(b) }()
+	go func() { errc <- m.Run(mA, mB) }()
+
+	echo, err := dh.Alice(a, dh.NISTGroup(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return echo, m
+}
+
+func TestChallenge34(t *testing.T) {
+	msg := []byte("Cooking MC's like a pound of bacon")
+	echo, m := runEchoWithMITM(t, attack.InjectPublicKeyP, msg)
+
+	// A and B still talk happily...
+	if string(echo) != string(msg) {
+		t.Fatalf("echo mismatch: %q", echo)
+	}
+	// ...while M reads both directions
+	if len(m.Recovered) != 2 {
+		t.Fatalf("recovered %d messages, want 2", len(m.Recovered))
+	}
+	for _, r := range m.Recovered {
+		if string(r) != string(msg) {
+			t.Fatalf("recovered %q, want %q", r, msg)
+		}
+	}
+}
+
+func TestChallenge35(t *testing.T) {
+	tests := []struct {
+		name string
+		inj  attack.Injection
+	}{
+		{name: "g=1", inj: attack.InjectG1},
+		{name: "g=p", inj: attack.InjectGP},
+		{name: "g=p-1", inj: attack.InjectGPMinus1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := []byte("negotiated groups are not authenticated")
+			echo, m := runEchoWithMITM(t, tt.inj, msg)
+			if string(echo) != string(msg) {
+				t.Fatalf("echo mismatch: %q", echo)
+			}
+			if len(m.Recovered) != 2 || string(m.Recovered[0]) != string(msg) {
+				t.Fatalf("MITM recovered %q", m.Recovered)
+			}
+		})
+	}
+}
+
+func TestChallenge36(t *testing.T) {
+	s := srp.NewServer(dh.NISTGroup())
+	s.Register("user@example.com", "correct horse battery staple")
+
+	c := &srp.Client{Group: s.Group, Email: "user@example.com", Password: "correct horse battery staple"}
+	if err := c.Login(s); err != nil {
+		t.Fatal(err)
+	}
+	c.Password = "Tr0ub4dor&3"
+	if err := c.Login(s); err == nil {
+		t.Fatal("login with wrong password succeeded")
+	}
+}
+
+func TestChallenge37(t *testing.T) {
+	s := srp.NewServer(dh.NISTGroup())
+	s.Register("user@example.com", "a password nobody will guess")
+
+	// A = 0, N and 2N all force the server's S to zero
+	for _, multiple := range []int64{0, 1, 2} {
+		if err := attack.LoginWithZeroKey(s, s.Group, "user@example.com", multiple); err != nil {
+			t.Fatalf("zero-key login with A=%d*N failed: %v", multiple, err)
+		}
+	}
+}
+
+func TestChallenge38(t *testing.T) {
+	group := dh.NISTGroup()
+
+	// The honest simplified protocol works
+	s := srp.NewSimpleServer(group)
+	s.Register("user@example.com", "sunshine")
+	c := &srp.Client{Group: group, Email: "user@example.com", Password: "sunshine"}
+	if err := c.LoginSimple(s); err != nil {
+		t.Fatal(err)
+	}
+
+	// Now the client talks to M instead, who cracks the proof offline
+	m := &attack.SimpleSRPMITM{Group: group, Salt: cu.RandomBytes(16)}
+	_ = c.LoginSimple(m)
+	dictionary := []string{"123456", "password", "qwerty", "letmein", "dragon", "sunshine", "monkey"}
+	got, err := m.CrackPassword(dictionary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "sunshine" {
+		t.Fatalf("cracked %q, want %q", got, "sunshine")
+	}
+}
+
+func TestChallenge39(t *testing.T) {
+	inv, err := rsa.InvMod(big.NewInt(17), big.NewInt(3120))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.Int64() != 2753 {
+		t.Fatalf("invmod(17, 3120) = %v, want 2753", inv)
+	}
+
+	k, err := rsa.GenerateKey(1024, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := big.NewInt(42)
+	if got := k.Decrypt(k.Encrypt(m)); got.Cmp(m) != 0 {
+		t.Fatalf("decrypt(encrypt(42)) = %v", got)
+	}
+
+	msg := []byte("textbook RSA")
+	ct, err := k.EncryptBytes(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := k.DecryptBytes(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pt) != string(msg) {
+		t.Fatalf("round trip mismatch: %q", pt)
+	}
+}
+
+func TestChallenge40(t *testing.T) {
+	msg := []byte("Hastad's broadcast attack")
+	var captures [3]attack.Broadcast
+	for i := range captures {
+		k, err := rsa.GenerateKey(1024, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, err := k.EncryptBytes(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		captures[i] = attack.Broadcast{C: new(big.Int).SetBytes(ct), N: k.N}
+	}
+
+	m, err := attack.HastadBroadcast(captures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(m.Bytes()) != string(msg) {
+		t.Fatalf("recovered %q, want %q", m.Bytes(), msg)
+	}
+}
@@ -0,0 +1,320 @@
+package set6
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/attack"
+	"github.com/jonathanlamela/go-cryptopals/pkg/base64"
+	"github.com/jonathanlamela/go-cryptopals/pkg/dsa"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+func TestChallenge41(t *testing.T) {
+	o, err := or.NewOracle41()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("{time: 1356304276, social: '555-55-5555'}")
+	ct, err := o.PublicKey().EncryptBytes(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The legitimate recipient decrypts it first...
+	if _, err := o.Decrypt(ct); err != nil {
+		t.Fatal(err)
+	}
+	// ...so the attacker cannot simply replay it
+	if _, err := o.Decrypt(ct); err == nil {
+		t.Fatal("server decrypted a replayed ciphertext")
+	}
+
+	got, err := attack.RecoverUnpadded(o, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(secret) {
+		t.Fatalf("recovered %q, want %q", got, secret)
+	}
+}
+
+func TestChallenge42(t *testing.T) {
+	tests := []struct {
+		name string
+		bits int
+		h    crypto.Hash
+		sum  func([]byte) []byte
+	}{
+		{name: "sha1 1024-bit", bits: 1024, h: crypto.SHA1, sum: func(b []byte) []byte { s := sha1.Sum(b); return s[:] }},
+		// SHA-256's longer DigestInfo needs a bigger key to leave room for garbage
+		{name: "sha256 2048-bit", bits: 2048, h: crypto.SHA256, sum: func(b []byte) []byte { s := sha256.Sum256(b); return s[:] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := rsa.GenerateKey(tt.bits, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			digest := tt.sum([]byte("hi mom"))
+			sig, err := attack.ForgePKCS1Signature(&k.PublicKey, tt.h, digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := k.VerifyPKCS1v15Sloppy(tt.h, digest, sig); err != nil {
+				t.Fatalf("broken verifier rejected forgery: %v", err)
+			}
+			if err := k.VerifyPKCS1v15(tt.h, digest, sig); err == nil {
+				t.Fatal("strict verifier accepted forgery")
+			}
+		})
+	}
+}
+
+func TestChallenge43(t *testing.T) {
+	params := dsa.ChallengeParameters()
+	y, _ := new(big.Int).SetString("84ad4719d044495496a3201c8ff484feb45b962e7302e56a392aee4"+
+		"abab3e4bdebf2955b4736012f21a08084056b19bcd7fee56048e004"+
+		"e44984e2f411788efdc837a0d2e5abb7b555039fd243ac01f0fb2ed"+
+		"1dec568280ce678e931868d23eb095fde9d3779191b8c0299d6e07b"+
+		"bb283e6633451e535c45513b2d33c99ea17", 16)
+	pub := &dsa.PublicKey{Parameters: params, Y: y}
+
+	msg := []byte("For those that envy a MC it can be hazardous to your health\n" +
+		"So be friendly, a matter of life and death, just like a etch-a-sketch\n")
+	digest := sha1.Sum(msg)
+	if hex.EncodeToString(digest[:]) != "d2d0714f014a9784047eaeccf956520045c45265" {
+		t.Fatalf("unexpected message hash %x", digest)
+	}
+	r, _ := new(big.Int).SetString("548099063082341131477253921760299949438196259240", 10)
+	s, _ := new(big.Int).SetString("857042759984254168557880549501802188789837994940", 10)
+	sig := &dsa.Signature{R: r, S: s}
+	if err := pub.Verify(digest[:], sig); err != nil {
+		t.Fatalf("challenge signature does not verify: %v", err)
+	}
+
+	fingerprint, _ := hex.DecodeString("0954edd5e0afe5542a4adf012611a91912a3ec16")
+	x, err := attack.RecoverDSAKeyFromWeakNonce(pub, digest[:], sig, fingerprint, 1<<16, runtime.NumCPU())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sha1.Sum([]byte(x.Text(16))); !bytes.Equal(got[:], fingerprint) {
+		t.Fatalf("recovered x with wrong fingerprint %x", got)
+	}
+}
+
+func TestChallenge44(t *testing.T) {
+	f, err := os.Open("../../data/data_44.txt")
+	if os.IsNotExist(err) {
+		// The corpus has to be downloaded from the challenge page; until it
+		// is, TestChallenge44Synthetic covers the same code paths.
+		t.Skip("data/data_44.txt not present")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	msgs, err := dsa.ParseSignedMessages(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	y, _ := new(big.Int).SetString("2d026f4bf30195ede3a088da85e398ef869611d0f68f0713d51c9c1a"+
+		"3a26c95105d915e2d8cdf26d056b86b8a7b85519b1c23cc3ecdc6062"+
+		"650462e3063bd179c2a6581519f674a61f1d89a1fff27171ebc1b93d"+
+		"4dc57bceb7ae2430f98a6a4d83d8279ee65d71c1203d2c96d65ebbf7"+
+		"cce9d32971c3de5084cce04a2e147821", 16)
+	pub := &dsa.PublicKey{Parameters: dsa.ChallengeParameters(), Y: y}
+	for i, m := range msgs {
+		if err := pub.Verify(m.Digest, &m.Sig); err != nil {
+			t.Fatalf("corpus signature %d does not verify: %v", i, err)
+		}
+	}
+	if len(attack.FindNonceReuse(msgs)) == 0 {
+		t.Fatal("no reused nonce found in the corpus")
+	}
+
+	x, err := attack.RecoverDSAKeyFromRepeatedNonce(pub, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := sha1.Sum([]byte(x.Text(16)))
+	if hex.EncodeToString(fingerprint[:]) != "ca8f6f7c66fa362d40760d135b763eb8527d3d52" {
+		t.Fatalf("recovered x with wrong fingerprint %x", fingerprint)
+	}
+}
+
+func TestChallenge44Synthetic(t *testing.T) {
+	// A corpus in the same format generated on the fly: 11 messages where
+	// two known pairs share a nonce.
+	k, err := dsa.GenerateKey(dsa.ChallengeParameters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reused := []*big.Int{big.NewInt(0xdead), big.NewInt(0xbeef)}
+	var corpus strings.Builder
+	for i := 0; i < 11; i++ {
+		msg := fmt.Sprintf("Line %d of some very sincere lyrics. ", i)
+		digest := sha1.Sum([]byte(msg))
+		nonce := dsa.RandomNonce
+		if i == 2 || i == 7 {
+			nonce = func(*big.Int) (*big.Int, error) { return reused[0], nil }
+		} else if i == 4 || i == 9 {
+			nonce = func(*big.Int) (*big.Int, error) { return reused[1], nil }
+		}
+		sig, err := k.Sign(digest[:], nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&corpus, "msg: %s\ns: %s\nr: %s\nm: %x\n", msg, sig.S, sig.R, digest)
+	}
+
+	msgs, err := dsa.ParseSignedMessages(strings.NewReader(corpus.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs := attack.FindNonceReuse(msgs)
+	if len(pairs) != 2 || pairs[0] != [2]int{2, 7} || pairs[1] != [2]int{4, 9} {
+		t.Fatalf("unexpected nonce reuse pairs %v", pairs)
+	}
+
+	x, err := attack.RecoverDSAKeyFromRepeatedNonce(&k.PublicKey, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Cmp(k.X) != 0 {
+		t.Fatal("recovered the wrong private key")
+	}
+}
+
+func TestChallenge45(t *testing.T) {
+	messages := []string{"Hello, world", "Goodbye, world"}
+	lax := []dsa.VerifyOption{dsa.SkipParameterChecks(), dsa.SkipRangeChecks()}
+
+	t.Run("g=0", func(t *testing.T) {
+		params := dsa.ChallengeParameters()
+		params.G = big.NewInt(0)
+		k, err := dsa.GenerateKey(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := k.Sign([]byte("anything"), dsa.RandomNonce); err == nil {
+			t.Fatal("signed under a zero generator")
+		}
+
+		sig := attack.ZeroGeneratorSignature()
+		for _, m := range messages {
+			digest := sha1.Sum([]byte(m))
+			if err := k.Verify(digest[:], sig, lax...); err != nil {
+				t.Fatalf("lax verifier rejected %q: %v", m, err)
+			}
+			if err := k.Verify(digest[:], sig); err == nil {
+				t.Fatalf("strict verifier accepted %q", m)
+			}
+			// Fixing the parameters is not enough on its own: r = 0 must be rejected too
+			if err := k.Verify(digest[:], sig, dsa.SkipParameterChecks()); err == nil {
+				t.Fatalf("range-checking verifier accepted %q", m)
+			}
+		}
+	})
+
+	t.Run("g=p+1", func(t *testing.T) {
+		params := dsa.ChallengeParameters()
+		params.G = new(big.Int).Add(params.P, big.NewInt(1))
+		k, err := dsa.GenerateKey(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sig, err := attack.MagicSignature(&k.PublicKey, big.NewInt(1337))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range messages {
+			digest := sha1.Sum([]byte(m))
+			if err := k.Verify(digest[:], sig, lax...); err != nil {
+				t.Fatalf("lax verifier rejected %q: %v", m, err)
+			}
+			if err := k.Verify(digest[:], sig); err == nil {
+				t.Fatalf("strict verifier accepted %q", m)
+			}
+		}
+	})
+}
+
+func TestChallenge46(t *testing.T) {
+	o, err := or.NewOracle46()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := base64.FromString("VGhhdCdzIHdoeSBJIGZvdW5kIHlvdSBkb24ndCBwbGF5IGFyb3VuZCB3aXRoIHRoZSBGdW5reSBDb2xkIE1lZGluYQ==").ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := o.PublicKey().EncryptBytes(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := 0
+	got, err := attack.RecoverFromParity(o, ct, func(upper []byte) {
+		steps++
+		if testing.Verbose() && steps%64 == 0 {
+			t.Logf("%q", upper)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps != o.Key.N.BitLen() {
+		t.Errorf("progress called %d times, want %d", steps, o.Key.N.BitLen())
+	}
+	if string(got) != string(secret) {
+		t.Fatalf("recovered %q", got)
+	}
+}
+
+// runBleichenbacher encrypts msg under a fresh bits-bit Oracle47 key and
+// recovers it through the padding oracle alone.
+func runBleichenbacher(t *testing.T, bits int, msg string) {
+	t.Helper()
+	o, err := or.NewOracle47(bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := o.PublicKey().EncryptPKCS1v15([]byte(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.PaddingOK(ct) {
+		t.Fatal("oracle rejected a freshly padded ciphertext")
+	}
+
+	got, err := attack.Bleichenbacher(o, ct, 5_000_000)
+	if err != nil {
+		t.Fatalf("%v after %d queries", err, o.Queries)
+	}
+	if string(got) != msg {
+		t.Fatalf("recovered %q", got)
+	}
+	t.Logf("%d-bit modulus: %d oracle queries", bits, o.Queries)
+}
+
+func TestChallenge47(t *testing.T) {
+	runBleichenbacher(t, 256, "kick it, CC")
+}
+
+func TestChallenge48(t *testing.T) {
+	runBleichenbacher(t, 768, "kick it, CC")
+}
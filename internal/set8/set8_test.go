@@ -0,0 +1,74 @@
+package set8
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/attack"
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+)
+
+func TestChallenge57(t *testing.T) {
+	g := dh.Challenge57Group()
+	j := new(big.Int).Sub(g.P, big.NewInt(1))
+	j.Div(j, g.Q)
+	var want []int64
+	for _, r := range attack.SmallFactors(j, 1<<16) {
+		want = append(want, r.Int64())
+	}
+	t.Logf("small factors of j: %v", want)
+	if len(want) == 0 || want[0] != 2 {
+		t.Fatalf("SmallFactors(j) = %v, want it to start with 2", want)
+	}
+
+	o, err := or.NewOracle57(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := attack.RecoverDHKeySubgroup(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Cmp(o.Key.Private) != 0 {
+		t.Fatalf("recovered x = %v, want %v", x, o.Key.Private)
+	}
+	if dh.ModExp(g.G, x, g.P).Cmp(o.PublicKey()) != 0 {
+		t.Fatal("g^x does not match Bob's public key")
+	}
+}
+
+func TestChallenge58(t *testing.T) {
+	g := dh.Challenge58Group()
+	tests := []struct {
+		y    string
+		bits uint
+	}{
+		{"7760073848032689505395005705677365876654629189298052775754597607446617558600394076764814236081991643094239886772481052254010323780165093955236429914607119", 20},
+		{"9388897478013399550694114614498790691034187453089355259602614074132918843899833277397448144245883225611726912025846772975325932794909655215329941809013733", 40},
+	}
+	for _, tt := range tests {
+		y, _ := new(big.Int).SetString(tt.y, 10)
+		b := new(big.Int).Lsh(big.NewInt(1), tt.bits)
+		x, err := attack.Kangaroo(g, g.G, y, big.NewInt(0), b, attack.PowerJumpsFor(b))
+		if err != nil {
+			t.Fatalf("index in [0, 2^%d]: %v", tt.bits, err)
+		}
+		if g.Exp(g.G, x).Cmp(y) != 0 {
+			t.Fatalf("g^%v != y", x)
+		}
+		t.Logf("index in [0, 2^%d]: %v", tt.bits, x)
+	}
+
+	o, err := or.NewOracle57(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := attack.RecoverDHKeyKangaroo(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Cmp(o.Key.Private) != 0 {
+		t.Fatalf("recovered x = %v, want %v", x, o.Key.Private)
+	}
+}
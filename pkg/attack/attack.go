@@ -0,0 +1,19 @@
+// Package attack holds the exported attacks against the oracles in pkg/oracle.
+// Each file groups the attacks for one technique:
+// - lengthext.go: Challenges 29-30 (MD length extension)
+// - timing.go: Challenges 31-32 (HMAC timing leak)
+// - dhmitm.go: Challenges 34-35 (DH key-fixing and g injection)
+// - srp.go: Challenges 37-38 (SRP zero key, simplified SRP dictionary attack)
+// - rsa.go: Challenge 40 onwards (RSA attacks)
+// - dsa.go: Challenge 43 onwards (DSA attacks)
+// - bleichenbacher.go: Challenges 47-48 (PKCS#1 v1.5 padding oracle)
+// - cbcmac.go: Challenges 49-50 (CBC-MAC forgeries)
+// - compression.go: Challenge 51 (compression ratio side channel)
+// - multicollision.go: Challenge 52 (Joux multicollisions, cascaded hashes)
+// - secondpreimage.go: Challenge 53 (expandable-message second preimages)
+// - nostradamus.go: Challenge 54 (diamond structure herding)
+// - wang.go: Challenge 55 (Wang et al. MD4 collisions)
+// - rc4bias.go: Challenge 56 (RC4 single-byte biases)
+// - subgroup.go: Challenge 57 (DH small-subgroup confinement)
+// - kangaroo.go: Challenge 58 (Pollard kangaroo, subgroups plus kangaroo)
+package attack
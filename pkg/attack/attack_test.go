@@ -0,0 +1,226 @@
+package attack
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+	"github.com/jonathanlamela/go-cryptopals/pkg/dsa"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/md4"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+	"github.com/jonathanlamela/go-cryptopals/pkg/sha1x"
+	"github.com/jonathanlamela/go-cryptopals/pkg/toymd"
+)
+
+func TestMDHashPadding(t *testing.T) {
+	for _, n := range []uint64{0, 3, 55, 56, 64, 200} {
+		if got, want := SHA1.Padding(n), sha1x.Padding(n); !bytes.Equal(got, want) {
+			t.Errorf("SHA1.Padding(%d) = %x, want %x", n, got, want)
+		}
+		if got, want := MD4.Padding(n), md4.Padding(n); !bytes.Equal(got, want) {
+			t.Errorf("MD4.Padding(%d) = %x, want %x", n, got, want)
+		}
+	}
+}
+
+func TestLengthExtensionKnownKey(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	msg := []byte("user=bob")
+	sum := sha1x.Sum(append(append([]byte(nil), key...), msg...))
+	verify := func(m, mac []byte) bool {
+		want := sha1x.Sum(append(append([]byte(nil), key...), m...))
+		return bytes.Equal(want[:], mac)
+	}
+	forgedMsg, _, err := LengthExtension(SHA1, msg, sum[:], []byte(";admin=true"), 32, verify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(forgedMsg, []byte(";admin=true")) {
+		t.Errorf("forged message %q missing suffix", forgedMsg)
+	}
+
+	if _, _, err := LengthExtension(SHA1, msg, sum[:], []byte(";admin=true"), 8, verify); err == nil {
+		t.Error("LengthExtension() should fail when maxKeyLen is shorter than the key")
+	}
+}
+
+func TestMedianAndTrimmedMean(t *testing.T) {
+	ds := []time.Duration{5, 1, 100, 3, 4}
+	if got := Median(ds); got != 4 {
+		t.Errorf("Median() = %v, want 4", got)
+	}
+	if got := Median(ds[:4]); got != 4 {
+		t.Errorf("Median() even = %v, want 4", got)
+	}
+	// Trimming 20% from each end drops 1 and 100
+	if got := TrimmedMean(0.2)(ds); got != 4 {
+		t.Errorf("TrimmedMean(0.2) = %v, want 4", got)
+	}
+	// Fractions that would trim everything fall back to the median
+	for _, f := range []float64{0.5, 0.9, 2} {
+		if got := TrimmedMean(f)(ds); got != 4 {
+			t.Errorf("TrimmedMean(%v) = %v, want 4", f, got)
+		}
+	}
+	if got := TrimmedMean(-1)(ds); got != 22 {
+		t.Errorf("TrimmedMean(-1) = %v, want the plain mean 22", got)
+	}
+}
+
+// plantedRC4Oracle stands in for Oracle56 with a keystream that is uniform
+// except for Z16 and Z32, which take their biased values far more often than
+// in real RC4, so a few thousand samples recover the cookie for certain.
+type plantedRC4Oracle struct {
+	cookie []byte
+	rng    *rand.Rand
+}
+
+func (o *plantedRC4Oracle) Encrypt(request []byte) ([]byte, error) {
+	ct := append(append([]byte(nil), request...), o.cookie...)
+	for i := range ct {
+		z := byte(o.rng.Intn(256))
+		if i == 15 && o.rng.Intn(4) == 0 {
+			z = z16Bias
+		}
+		if i == 31 && o.rng.Intn(8) == 0 {
+			z = z32Bias
+		}
+		ct[i] ^= z
+	}
+	return ct, nil
+}
+
+func TestRecoverRC4CookiePlantedBias(t *testing.T) {
+	cookie := []byte("BE SURE TO DRINK YOUR OVALTINE")
+	// One worker keeps the stand-in's random stream, and so the test, deterministic
+	o := &plantedRC4Oracle{cookie: cookie, rng: rand.New(rand.NewSource(56))}
+	got, err := RecoverRC4Cookie(o, 4096, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, cookie) {
+		t.Errorf("RecoverRC4Cookie() = %q, want %q", got, cookie)
+	}
+}
+
+func TestRecoverRC4CookieIndices(t *testing.T) {
+	o := or.NewOracle56([]byte("0123456789abcdefghijklmnopqrstuvwxyz"))
+	// Bytes past 31 are never under Z16 or Z32
+	for _, i := range []int{-1, 32, 40} {
+		if _, err := RecoverRC4Cookie(o, 1, 1, []int{i}); err != errors.ErrInvalidLength {
+			t.Errorf("RecoverRC4Cookie(index %d) error = %v, want ErrInvalidLength", i, err)
+		}
+	}
+	got, err := RecoverRC4Cookie(o, 16, 2, []int{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 36 {
+		t.Errorf("RecoverRC4Cookie() length = %d, want the cookie length 36", len(got))
+	}
+}
+
+func TestKangarooInvalidParameters(t *testing.T) {
+	g := dh.NewGroup(big.NewInt(23), big.NewInt(5))
+	zero, ten := big.NewInt(0), big.NewInt(10)
+	huge := Jumps{Hops: []*big.Int{new(big.Int).Lsh(big.NewInt(1), 80)}, Index: func(*big.Int) int { return 0 }}
+	tests := []struct {
+		name  string
+		a, b  *big.Int
+		jumps Jumps
+	}{
+		{name: "a > b", a: ten, b: zero, jumps: PowerJumps(3)},
+		{name: "no hops", a: zero, b: ten, jumps: PowerJumps(0)},
+		{name: "zero hop", a: zero, b: ten, jumps: Jumps{Hops: []*big.Int{zero}, Index: func(*big.Int) int { return 0 }}},
+		{name: "mean overflows", a: zero, b: ten, jumps: huge},
+	}
+	for _, tt := range tests {
+		if _, err := Kangaroo(g, g.G, big.NewInt(4), tt.a, tt.b, tt.jumps); err != errors.ErrInvalidParameters {
+			t.Errorf("%s: Kangaroo() error = %v, want ErrInvalidParameters", tt.name, err)
+		}
+	}
+}
+
+func TestBuildDiamondInvalidK(t *testing.T) {
+	h, err := toymd.New(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []int{-1, 0, 16, 20} {
+		if _, err := BuildDiamond(h, k, 1); err != errors.ErrInvalidParameters {
+			t.Errorf("BuildDiamond(k=%d) error = %v, want ErrInvalidParameters", k, err)
+		}
+	}
+}
+
+func TestRecoverDSAKeyFromWeakNonceFingerprint(t *testing.T) {
+	k, err := dsa.GenerateKey(dsa.ChallengeParameters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha1x.Sum([]byte("fingerprinted"))
+	sig, err := k.Sign(digest[:], func(*big.Int) (*big.Int, error) { return big.NewInt(9), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := sha1x.Sum([]byte(k.X.Text(16)))
+	x, err := RecoverDSAKeyFromWeakNonce(&k.PublicKey, digest[:], sig, fp[:], 16, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Cmp(k.X) != 0 {
+		t.Fatal("recovered the wrong private key")
+	}
+	wrong := bytes.Repeat([]byte{0xaa}, len(fp))
+	if _, err := RecoverDSAKeyFromWeakNonce(&k.PublicKey, digest[:], sig, wrong, 16, 2); err != errors.ErrKeyRecoveryFailed {
+		t.Errorf("RecoverDSAKeyFromWeakNonce() with a wrong fingerprint error = %v, want ErrKeyRecoveryFailed", err)
+	}
+}
+
+func TestSessionNext(t *testing.T) {
+	tests := []struct {
+		known, want string
+	}{
+		{known: "", want: sessionAlphabet},
+		{known: "ab", want: sessionAlphabet + "="},
+		{known: "abc", want: sessionAlphabet + "="},
+		{known: "abcd", want: sessionAlphabet + "\n"},
+		{known: "abcdef=", want: "="},
+		{known: "abcdef==", want: "\n"},
+		{known: "abcdefg=", want: "\n"},
+	}
+	for _, tt := range tests {
+		if got := sessionNext(tt.known); got != tt.want {
+			t.Errorf("sessionNext(%q) = %q, want %q", tt.known, got, tt.want)
+		}
+	}
+}
+
+func TestPickSlowestStep(t *testing.T) {
+	ms := time.Millisecond
+	samples := func(lead time.Duration) [][]time.Duration {
+		return [][]time.Duration{{10*ms + lead}, {10*ms + 20*time.Microsecond}, {10 * ms}}
+	}
+	cands := []int{7, 8, 9}
+	tests := []struct {
+		name string
+		lead time.Duration
+		step time.Duration
+		want bool
+	}{
+		{name: "no step yet", lead: 300 * time.Microsecond, want: true},
+		{name: "about one step", lead: ms, step: ms, want: true},
+		{name: "well short of a step", lead: 300 * time.Microsecond, step: ms, want: false},
+		{name: "several steps", lead: 3 * ms, step: ms, want: false},
+	}
+	for _, tt := range tests {
+		best, _, ok := pickSlowest(cands, samples(tt.lead), Median, tt.step)
+		if best != 7 || ok != tt.want {
+			t.Errorf("%s: pickSlowest() = %d, %v, want 7, %v", tt.name, best, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package attack
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+// interval is a closed range [a, b] that still contains the plaintext.
+type interval struct {
+	a, b *big.Int
+}
+
+// bleichenbacher carries the state shared by the steps of the attack.
+type bleichenbacher struct {
+	o          *or.Oracle47
+	pub        *rsa.PublicKey
+	c          *big.Int
+	twoB       *big.Int
+	threeB     *big.Int
+	maxQueries int
+}
+
+// conforming reports whether c*s^e decrypts to a 00 02 block.
+func (bb *bleichenbacher) conforming(s *big.Int) bool {
+	c := bb.pub.Encrypt(s)
+	c.Mul(c, bb.c).Mod(c, bb.pub.N)
+	return bb.o.PaddingOK(c.FillBytes(make([]byte, bb.pub.Size())))
+}
+
+// searchFrom returns the first s >= start that yields a conforming
+// ciphertext (steps 2a and 2b).
+func (bb *bleichenbacher) searchFrom(start *big.Int) (*big.Int, error) {
+	s := new(big.Int).Set(start)
+	for ; bb.o.Queries < bb.maxQueries; s.Add(s, big.NewInt(1)) {
+		if bb.conforming(s) {
+			return s, nil
+		}
+	}
+	return nil, errors.ErrPaddingOracleFailed
+}
+
+// searchInterval is step 2c: with a single interval [a, b] left, walk r from
+// 2(b*s - 2B)/n and try every s in [(2B + rn)/b, (3B + rn)/a). This roughly
+// halves the interval on each success.
+func (bb *bleichenbacher) searchInterval(m interval, prev *big.Int) (*big.Int, error) {
+	n := bb.pub.N
+	r := new(big.Int).Mul(m.b, prev)
+	r.Sub(r, bb.twoB).Lsh(r, 1)
+	r = ceilDiv(r, n)
+	for ; bb.o.Queries < bb.maxQueries; r.Add(r, big.NewInt(1)) {
+		rn := new(big.Int).Mul(r, n)
+		lo := ceilDiv(new(big.Int).Add(bb.twoB, rn), m.b)
+		hi := ceilDiv(new(big.Int).Add(bb.threeB, rn), m.a)
+		for s := lo; s.Cmp(hi) < 0; s.Add(s, big.NewInt(1)) {
+			if bb.conforming(s) {
+				return s, nil
+			}
+		}
+	}
+	return nil, errors.ErrPaddingOracleFailed
+}
+
+// narrow is step 3: for each interval and each r with
+// (a*s - 3B + 1)/n <= r <= (b*s - 2B)/n, intersect it with
+// [(2B + rn)/s, (3B - 1 + rn)/s], then merge the pieces.
+func (bb *bleichenbacher) narrow(set []interval, s *big.Int) []interval {
+	n := bb.pub.N
+	var next []interval
+	for _, m := range set {
+		rLo := new(big.Int).Mul(m.a, s)
+		rLo.Sub(rLo, bb.threeB).Add(rLo, big.NewInt(1))
+		rLo = ceilDiv(rLo, n)
+		rHi := new(big.Int).Mul(m.b, s)
+		rHi.Sub(rHi, bb.twoB).Div(rHi, n)
+		for r := rLo; r.Cmp(rHi) <= 0; r.Add(r, big.NewInt(1)) {
+			rn := new(big.Int).Mul(r, n)
+			a := ceilDiv(new(big.Int).Add(bb.twoB, rn), s)
+			if a.Cmp(m.a) < 0 {
+				a.Set(m.a)
+			}
+			b := new(big.Int).Add(bb.threeB, rn)
+			b.Sub(b, big.NewInt(1)).Div(b, s)
+			if b.Cmp(m.b) > 0 {
+				b.Set(m.b)
+			}
+			if a.Cmp(b) <= 0 {
+				next = append(next, interval{a, b})
+			}
+		}
+	}
+	return mergeIntervals(next)
+}
+
+// mergeIntervals sorts the intervals and fuses any that overlap.
+func mergeIntervals(set []interval) []interval {
+	sort.Slice(set, func(i, j int) bool { return set[i].a.Cmp(set[j].a) < 0 })
+	var out []interval
+	for _, m := range set {
+		if last := len(out) - 1; last >= 0 && m.a.Cmp(out[last].b) <= 0 {
+			if m.b.Cmp(out[last].b) > 0 {
+				out[last].b = m.b
+			}
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// ceilDiv returns ceil(x / y) for y > 0.
+func ceilDiv(x, y *big.Int) *big.Int {
+	q, m := new(big.Int).DivMod(x, y, new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// Bleichenbacher implements Challenges 47-48, Bleichenbacher's 1998 attack
+// on PKCS#1 v1.5 encryption. c must already be PKCS conforming, so step 1
+// (blinding) is skipped with s0 = 1. Starting from M = {[2B, 3B-1]} with
+// B = 2^(8(k-2)), each conforming c*s^e narrows M until a single value is
+// left; that block is unpadded and returned. The attack gives up after
+// maxQueries oracle calls; the count is left in o.Queries.
+func Bleichenbacher(o *or.Oracle47, c []byte, maxQueries int) ([]byte, error) {
+	pub := o.PublicKey()
+	B := new(big.Int).Lsh(big.NewInt(1), uint(8*(pub.Size()-2)))
+	bb := &bleichenbacher{
+		o:          o,
+		pub:        pub,
+		c:          new(big.Int).SetBytes(c),
+		twoB:       new(big.Int).Lsh(B, 1),
+		threeB:     new(big.Int).Mul(B, big.NewInt(3)),
+		maxQueries: o.Queries + maxQueries,
+	}
+	set := []interval{{new(big.Int).Set(bb.twoB), new(big.Int).Sub(bb.threeB, big.NewInt(1))}}
+
+	// Step 2a: the smallest s that can make m*s wrap back into [2B, 3B)
+	s, err := bb.searchFrom(ceilDiv(pub.N, bb.threeB))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		set = bb.narrow(set, s)
+		if len(set) == 0 {
+			return nil, errors.ErrPaddingOracleFailed
+		}
+		// Step 4
+		if len(set) == 1 && set[0].a.Cmp(set[0].b) == 0 {
+			return rsa.UnpadPKCS1v15(set[0].a.FillBytes(make([]byte, pub.Size())))
+		}
+		if len(set) > 1 {
+			// Step 2b
+			s, err = bb.searchFrom(new(big.Int).Add(s, big.NewInt(1)))
+		} else {
+			s, err = bb.searchInterval(set[0], s)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package attack
+
+import (
+	"bytes"
+	"crypto/aes"
+	"fmt"
+
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+)
+
+// ForgeTransferIV implements the v1 half of Challenge 49. The IV only
+// touches the first block, so XORing it with the difference between
+// "from=<attacker>..." and "from=<victim>..." makes the server decrypt a
+// different first block to the same chain value: the MAC still holds.
+// It returns a request moving amount from the victim to the attacker.
+func ForgeTransferIV(o *or.Oracle49, amount int) ([]byte, error) {
+	req := o.TransferV1(o.Attacker, amount)
+	msg, iv, mac := req[:len(req)-32], req[len(req)-32:len(req)-16], req[len(req)-16:]
+
+	forged := []byte(fmt.Sprintf("from=%s&to=%s&amount=%d", o.Victim, o.Attacker, amount))
+	if len(forged) != len(msg) || !bytes.Equal(forged[16:], msg[16:]) {
+		return nil, errors.ErrForgeryFailed
+	}
+	delta := cu.CryptoBytes(msg[:16]).Xor(forged[:16])
+	newIV := cu.CryptoBytes(iv).Xor(delta)
+
+	return append(append(forged, newIV...), mac...), nil
+}
+
+// ExtendCBCMAC glues ext onto msg, whose zero-IV CBC-MAC is mac. XORing mac
+// into the first block of ext restarts the chain from zero, so the glued
+// message has the same zero-IV CBC-MAC as ext alone.
+func ExtendCBCMAC(msg, mac, ext []byte) []byte {
+	out := cu.ZeroPad(msg, 16)
+	out = append(out, cu.CryptoBytes(ext[:16]).Xor(mac)...)
+	return append(out, ext[16:]...)
+}
+
+// ForgeTransferExtension implements the v2 half of Challenge 49: the IV is
+// fixed, but the attacker can extend a captured victim request with a
+// transaction list of their own, signed by the client for their account.
+// The first block of the extension turns into garbage that the server
+// skips; the trailing transaction pays amount to the attacker.
+func ForgeTransferExtension(o *or.Oracle49, amount int) ([]byte, error) {
+	captured := o.CaptureV2()
+	msg, mac := captured[:len(captured)-16], captured[len(captured)-16:]
+
+	own := o.TransferV2([]or.Transfer{{To: o.Attacker, Amount: 1}, {To: o.Attacker, Amount: amount}})
+	ext, extMAC := own[:len(own)-16], own[len(own)-16:]
+	if len(ext) < 16 {
+		return nil, errors.ErrForgeryFailed
+	}
+
+	forged := ExtendCBCMAC(msg, mac, ext)
+	return append(forged, extMAC...), nil
+}
+
+// ForgeCBCMACHash implements Challenge 50: since the CBC-MAC key is public,
+// anyone can compute the chain value h after a block-aligned prefix. A glue
+// block h XOR target[:16] then brings the chain back to where the target's
+// first block leaves it, and the rest of target finishes with its hash.
+//
+// The prefix is payload followed by "//", spaces up to a block boundary and
+// one block of digits, so the glue block and the tail of the target's first
+// line end up in a JavaScript comment. The digits count up until the glue is
+// printable ASCII too, which takes about (256/95)^16, some 2^23, tries; each
+// costs one AES call. payload must be printable and single-line.
+func ForgeCBCMACHash(target, payload []byte) ([]byte, error) {
+	if len(target) < 16 || bytes.ContainsAny(payload, "\r\n") {
+		return nil, errors.ErrForgeryFailed
+	}
+	key, iv := []byte("YELLOW SUBMARINE"), make([]byte, 16)
+	prefix := append(append([]byte(nil), payload...), "//"...)
+	for len(prefix)%16 != 0 {
+		prefix = append(prefix, ' ')
+	}
+	chain, err := cu.CryptoBytes(prefix).SSLCBCEncrypt(key, iv, false)
+	if err != nil {
+		return nil, err
+	}
+	h := chain[len(chain)-16:]
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	filler, glue := make([]byte, 16), make([]byte, 16)
+	for attempt := 0; attempt < maxGlueAttempts; attempt++ {
+		copy(filler, fmt.Sprintf("%016d", attempt))
+		// CBC step over the filler block, then the glue that undoes it
+		for i := range glue {
+			glue[i] = h[i] ^ filler[i]
+		}
+		block.Encrypt(glue, glue)
+		for i := range glue {
+			glue[i] ^= target[i]
+		}
+		if printable(glue) {
+			forged := append(append(prefix, filler...), glue...)
+			return append(forged, target[16:]...), nil
+		}
+	}
+	return nil, errors.ErrForgeryFailed
+}
+
+// maxGlueAttempts leaves ForgeCBCMACHash ample room over the ~2^23 filler
+// blocks it needs on average.
+const maxGlueAttempts = 1 << 28
+
+// printable reports whether b is entirely printable ASCII, which also
+// rules out the line terminators that would end a // comment.
+func printable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
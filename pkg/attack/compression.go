@@ -0,0 +1,133 @@
+package attack
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+)
+
+const (
+	// sessionAlphabet holds the characters of a base64 session id other
+	// than its '=' padding.
+	sessionAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	// filler is prepended to probes to shift where the compressed length
+	// crosses a block boundary. Its characters are all distinct and absent
+	// from the request, so they never compress against anything.
+	filler = "!@#$%^&*()[]{}<>~"
+	// tail is appended after the guess to break ties, for the same reason
+	// and under the same constraints as filler, with characters of its own.
+	tail = "`|;'\"?_"
+
+	maxSessionLen = 128
+	crimeBeam     = 2
+)
+
+// crimeScore sums the leaked lengths of "sessionid=" + guess behind every
+// prefix of filler. A single length is too coarse: CBC rounds it to a
+// block, and even under CTR Huffman coding can hide a one-character gain.
+// Sliding the filler moves the block boundaries and the code tables, so the
+// sum tracks the compressed size far more finely than any one query.
+func crimeScore(o *or.Oracle51, guess string) int {
+	total := 0
+	for pad := 0; pad <= len(filler); pad++ {
+		total += o.Length([]byte(filler[:pad] + "sessionid=" + guess))
+	}
+	return total
+}
+
+// crimeTieBreak is crimeScore repeated behind every prefix of tail as well.
+// Early on a guess ending in a character the request already uses often,
+// like 'o' or 's', costs so little as a literal that it ties with the
+// right one even over all the filler shifts; moving the end of the probe
+// too separates them. It costs len(tail) times more queries, so it only
+// ranks guesses tied for the best score.
+func crimeTieBreak(o *or.Oracle51, guess string) int {
+	total := 0
+	for pad := 1; pad <= len(tail); pad++ {
+		total += crimeScore(o, guess+tail[:pad])
+	}
+	return total
+}
+
+// sessionNext returns the characters that may follow known in a base64
+// session id ended by the newline of the Cookie header. '=' only pads the
+// last group of four, and the newline only comes after a whole group, so a
+// guess can neither stop half way through a group nor run on past padding.
+func sessionNext(known string) string {
+	n := len(known)
+	switch {
+	case strings.HasSuffix(known, "=") && n%4 == 3:
+		return "="
+	case strings.HasSuffix(known, "="):
+		return "\n"
+	case n%4 == 0 && n > 0:
+		return sessionAlphabet + "\n"
+	case n%4 >= 2:
+		return sessionAlphabet + "="
+	}
+	return sessionAlphabet
+}
+
+// RecoverSessionID implements Challenge 51, a CRIME-style attack. The
+// compressed request shrinks when the attacker's content repeats part of
+// the cookie, so "sessionid=" plus a correct guess compresses better than a
+// wrong one. The cookie is grown one character at a time, keeping the few
+// best-scoring guesses, until the newline after it is the best extension.
+// Guesses tied for the best score are ranked by crimeTieBreak first, so the
+// right one is not dropped from the beam on a coin flip.
+func RecoverSessionID(o *or.Oracle51) (string, error) {
+	type scored struct {
+		guess    string
+		score    int
+		tieBreak int
+	}
+	beam := []string{""}
+	for len(beam[0]) < maxSessionLen {
+		var next []scored
+		for _, known := range beam {
+			ext := sessionNext(known)
+			for i := range ext {
+				next = append(next, scored{guess: known + ext[i:i+1]})
+			}
+		}
+		// Every guess is scored independently, so spread them over goroutines
+		scoreAll := func(ss []scored, f func(*scored)) {
+			var wg sync.WaitGroup
+			for i := range ss {
+				wg.Add(1)
+				go func(s *scored) {
+					defer wg.Done()
+					f(s)
+				}(&ss[i])
+			}
+			wg.Wait()
+		}
+		scoreAll(next, func(s *scored) { s.score = crimeScore(o, s.guess) })
+		sort.SliceStable(next, func(i, j int) bool { return next[i].score < next[j].score })
+		tied := 1
+		for tied < len(next) && next[tied].score == next[0].score {
+			tied++
+		}
+		if tied > 1 {
+			scoreAll(next[:tied], func(s *scored) { s.tieBreak = crimeTieBreak(o, s.guess) })
+			sort.SliceStable(next[:tied], func(i, j int) bool { return next[i].tieBreak < next[j].tieBreak })
+		}
+
+		if best := next[0].guess; strings.HasSuffix(best, "\n") {
+			return strings.TrimSuffix(best, "\n"), nil
+		}
+		beam = beam[:0]
+		for _, s := range next {
+			if len(beam) == crimeBeam {
+				break
+			}
+			if !strings.HasSuffix(s.guess, "\n") {
+				beam = append(beam, s.guess)
+			}
+		}
+	}
+	return "", errors.ErrCompressionAttackFailed
+}
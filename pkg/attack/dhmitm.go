@@ -0,0 +1,137 @@
+package attack
+
+import (
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// Injection selects what the DH man-in-the-middle tampers with.
+type Injection int
+
+const (
+	// InjectPublicKeyP replaces both public keys with p, forcing s = 0 (Challenge 34).
+	InjectPublicKeyP Injection = iota
+	// InjectG1 negotiates g = 1, forcing s = 1 (Challenge 35).
+	InjectG1
+	// InjectGP negotiates g = p, forcing s = 0 (Challenge 35).
+	InjectGP
+	// InjectGPMinus1 negotiates g = p-1, forcing s to 1 or p-1 (Challenge 35).
+	InjectGPMinus1
+)
+
+// DHMITM sits between A and B in the echo protocol of pkg/dh and reads
+// their traffic by forcing the shared secret to a predictable value.
+type DHMITM struct {
+	Injection Injection
+	// Recovered collects every plaintext the relay decrypted, in order.
+	Recovered [][]byte
+}
+
+// Run relays one echo session between the connection facing A and the one
+// facing B, tampering according to m.Injection and recording the plaintexts.
+func (m *DHMITM) Run(toA, toB *dh.Conn) error {
+	defer toA.Close()
+	defer toB.Close()
+
+	// Group negotiation: A proposes, B acknowledges, A adopts the ack
+	proposal, err := toA.Recv()
+	if err != nil {
+		return err
+	}
+	if proposal.P == nil || proposal.G == nil {
+		return errors.ErrUnexpectedMessage
+	}
+	p := proposal.P
+	if g := m.injectedG(p); g != nil {
+		proposal.G = g
+	}
+	if err := relay(toB, toA, proposal); err != nil {
+		return err
+	}
+
+	// Public keys: A first, then B
+	pubA, err := toA.Recv()
+	if err != nil {
+		return err
+	}
+	if m.Injection == InjectPublicKeyP {
+		pubA.Public = p
+	}
+	if err := toB.Send(pubA); err != nil {
+		return err
+	}
+	pubB, err := toB.Recv()
+	if err != nil {
+		return err
+	}
+	if m.Injection == InjectPublicKeyP {
+		pubB.Public = p
+	}
+	if err := toA.Send(pubB); err != nil {
+		return err
+	}
+
+	key := dh.SessionKey(m.forcedSecret(p, pubA.Public, pubB.Public))
+
+	// Encrypted messages: read each one, then pass it along untouched
+	for _, hop := range []struct{ from, to *dh.Conn }{{toA, toB}, {toB, toA}} {
+		msg, err := hop.from.Recv()
+		if err != nil {
+			return err
+		}
+		plain, err := dh.OpenMessage(key, msg.Ciphertext)
+		if err != nil {
+			return errors.ErrMITMFailed
+		}
+		m.Recovered = append(m.Recovered, plain)
+		if err := hop.to.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relay forwards msg to dst and passes dst's single reply back to src.
+func relay(dst, src *dh.Conn, msg dh.Message) error {
+	if err := dst.Send(msg); err != nil {
+		return err
+	}
+	reply, err := dst.Recv()
+	if err != nil {
+		return err
+	}
+	return src.Send(reply)
+}
+
+// injectedG returns the generator to substitute, or nil to leave it alone.
+func (m *DHMITM) injectedG(p *big.Int) *big.Int {
+	switch m.Injection {
+	case InjectG1:
+		return big.NewInt(1)
+	case InjectGP:
+		return new(big.Int).Set(p)
+	case InjectGPMinus1:
+		return new(big.Int).Sub(p, big.NewInt(1))
+	}
+	return nil
+}
+
+// forcedSecret predicts the shared secret both sides computed.
+func (m *DHMITM) forcedSecret(p, pubA, pubB *big.Int) *big.Int {
+	switch m.Injection {
+	case InjectG1:
+		return big.NewInt(1)
+	case InjectGPMinus1:
+		// (p-1)^x is 1 for even x and p-1 for odd x, so s = (p-1)^(ab)
+		// is p-1 only when both public keys are p-1
+		one := big.NewInt(1)
+		if pubA.Cmp(one) == 0 || pubB.Cmp(one) == 0 {
+			return one
+		}
+		return new(big.Int).Sub(p, one)
+	}
+	// InjectPublicKeyP and InjectGP: everything is a power of p, i.e. 0
+	return big.NewInt(0)
+}
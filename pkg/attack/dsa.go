@@ -0,0 +1,133 @@
+package attack
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dsa"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+	"github.com/jonathanlamela/go-cryptopals/pkg/sha1x"
+)
+
+// RecoverDSAKeyFromWeakNonce implements Challenge 43: the signer drew k from
+// [0, maxK], so every candidate is tried across workers goroutines. A k is
+// right when (g^k mod p) mod q equals r; the x it yields is then confirmed
+// against the public key before being returned. A non-nil fingerprint, the
+// SHA-1 of x's hex encoding as the challenge publishes it, must match too.
+func RecoverDSAKeyFromWeakNonce(pub *dsa.PublicKey, digest []byte, sig *dsa.Signature, fingerprint []byte, maxK int64, workers int) (*big.Int, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		once  sync.Once
+		found *big.Int
+		done  = make(chan struct{})
+		wg    sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(start int64) {
+			defer wg.Done()
+			k, r := new(big.Int), new(big.Int)
+			for c := start; c <= maxK; c += int64(workers) {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				k.SetInt64(c)
+				r.Exp(pub.G, k, pub.P).Mod(r, pub.Q)
+				if r.Cmp(sig.R) != 0 {
+					continue
+				}
+				x, err := dsa.PrivateKeyFromNonce(pub.Parameters, digest, sig, k)
+				if err != nil || new(big.Int).Exp(pub.G, x, pub.P).Cmp(pub.Y) != 0 {
+					continue
+				}
+				if fp := sha1x.Sum([]byte(x.Text(16))); fingerprint != nil && !bytes.Equal(fp[:], fingerprint) {
+					continue
+				}
+				once.Do(func() {
+					found = x
+					close(done)
+				})
+				return
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+	if found == nil {
+		return nil, errors.ErrKeyRecoveryFailed
+	}
+	return found, nil
+}
+
+// FindNonceReuse returns index pairs of corpus entries signed with the same
+// k. Since r = (g^k mod p) mod q depends only on k, equal r values give it away.
+// Each later duplicate is paired with the first entry that carried its r.
+func FindNonceReuse(msgs []dsa.SignedMessage) [][2]int {
+	first := map[string]int{}
+	var pairs [][2]int
+	for i, m := range msgs {
+		key := m.Sig.R.String()
+		if j, ok := first[key]; ok {
+			pairs = append(pairs, [2]int{j, i})
+			continue
+		}
+		first[key] = i
+	}
+	return pairs
+}
+
+// RecoverDSAKeyFromRepeatedNonce implements Challenge 44: for any two
+// signatures sharing k, s1 - s2 = k^-1 (m1 - m2) mod q, so
+// k = (m1 - m2) / (s1 - s2) mod q, and x follows from either signature.
+// Every colliding pair is tried until one yields x matching pub.Y.
+func RecoverDSAKeyFromRepeatedNonce(pub *dsa.PublicKey, msgs []dsa.SignedMessage) (*big.Int, error) {
+	q := pub.Q
+	for _, pair := range FindNonceReuse(msgs) {
+		a, b := msgs[pair[0]], msgs[pair[1]]
+		ds := new(big.Int).Sub(a.Sig.S, b.Sig.S)
+		ds.Mod(ds, q)
+		dsInv, err := rsa.InvMod(ds, q)
+		if err != nil {
+			// Identical s values: the same message signed twice teaches nothing
+			continue
+		}
+		k := new(big.Int).Sub(new(big.Int).SetBytes(a.Digest), new(big.Int).SetBytes(b.Digest))
+		k.Mul(k, dsInv).Mod(k, q)
+
+		x, err := dsa.PrivateKeyFromNonce(pub.Parameters, a.Digest, &a.Sig, k)
+		if err != nil {
+			continue
+		}
+		if new(big.Int).Exp(pub.G, x, pub.P).Cmp(pub.Y) == 0 {
+			return x, nil
+		}
+	}
+	return nil, errors.ErrKeyRecoveryFailed
+}
+
+// ZeroGeneratorSignature implements the g = 0 half of Challenge 45: with
+// g = 0 every y and every g^u1 vanish, so v is always 0 and r = 0 verifies
+// for any message and any s. Only a verifier skipping 0 < r < q accepts it.
+func ZeroGeneratorSignature() *dsa.Signature {
+	return &dsa.Signature{R: big.NewInt(0), S: big.NewInt(1)}
+}
+
+// MagicSignature implements the g = p+1 half of Challenge 45: g^u1 is then 1
+// mod p, so v = y^(r/s). Picking r = (y^z mod p) mod q and s = r/z mod q
+// makes v = y^z for an arbitrary z, a signature valid for every message.
+func MagicSignature(pub *dsa.PublicKey, z *big.Int) (*dsa.Signature, error) {
+	r := new(big.Int).Exp(pub.Y, z, pub.P)
+	r.Mod(r, pub.Q)
+	zInv, err := rsa.InvMod(z, pub.Q)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).Mul(r, zInv)
+	s.Mod(s, pub.Q)
+	return &dsa.Signature{R: r, S: s}, nil
+}
@@ -0,0 +1,127 @@
+package attack
+
+import (
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+)
+
+// KangarooGroup is a cyclic group with big.Int elements for Kangaroo to
+// hop in; *dh.Group implements it for the integers modulo p.
+type KangarooGroup interface {
+	// Mul is the group operation
+	Mul(x, y *big.Int) *big.Int
+	// Exp applies the group operation to e copies of x
+	Exp(x, e *big.Int) *big.Int
+}
+
+// Jumps is the pseudo-random jump function of the kangaroos: a kangaroo at
+// y hops Hops[Index(y)] steps. It only has to be deterministic, so both
+// kangaroos take the same path once they land on the same element.
+type Jumps struct {
+	Hops  []*big.Int
+	Index func(y *big.Int) int
+}
+
+// PowerJumps returns the jump function of Challenge 58, 2^(y mod k). Its
+// mean is about 2^k/k, which should be near the square root of the
+// interval searched.
+func PowerJumps(k int) Jumps {
+	hops := make([]*big.Int, k)
+	for i := range hops {
+		hops[i] = new(big.Int).Lsh(big.NewInt(1), uint(i))
+	}
+	K := big.NewInt(int64(k))
+	return Jumps{
+		Hops: hops,
+		Index: func(y *big.Int) int {
+			return int(new(big.Int).Mod(y, K).Int64())
+		},
+	}
+}
+
+// PowerJumpsFor picks k for PowerJumps so that the mean jump is close to
+// half the square root of an interval of the given width.
+func PowerJumpsFor(width *big.Int) Jumps {
+	return PowerJumps(width.BitLen()/2 + 4)
+}
+
+// Kangaroo implements Pollard's kangaroo (lambda) algorithm: it returns x
+// in [a, b] with g^x = y in grp. A tame kangaroo starts at g^b and makes
+// 4 times the mean jump hops, leaving a trap at its final position and
+// remembering the distance travelled. A wild kangaroo starts at y; if it
+// lands on the tame path it follows it into the trap, revealing x, and if
+// it runs past the trap x is not in the interval, or the walk was unlucky.
+//
+// It returns ErrInvalidParameters for a > b, no hops or a hop that is not
+// positive, and a mean jump too large for the tame kangaroo's hop count.
+func Kangaroo(grp KangarooGroup, g, y, a, b *big.Int, jumps Jumps) (*big.Int, error) {
+	if a.Cmp(b) > 0 || len(jumps.Hops) == 0 || jumps.Index == nil {
+		return nil, errors.ErrInvalidParameters
+	}
+	steps := make([]*big.Int, len(jumps.Hops))
+	mean := new(big.Int)
+	for i, h := range jumps.Hops {
+		if h.Sign() <= 0 {
+			return nil, errors.ErrInvalidParameters
+		}
+		steps[i] = grp.Exp(g, h)
+		mean.Add(mean, h)
+	}
+	mean.Div(mean, big.NewInt(int64(len(jumps.Hops))))
+	if mean.Lsh(mean, 2); !mean.IsInt64() {
+		return nil, errors.ErrInvalidParameters
+	}
+	n := mean.Int64()
+
+	// Tame kangaroo: n hops from g^b, travelling xT
+	xT := new(big.Int)
+	yT := grp.Exp(g, b)
+	for i := int64(0); i < n; i++ {
+		j := jumps.Index(yT)
+		xT.Add(xT, jumps.Hops[j])
+		yT = grp.Mul(yT, steps[j])
+	}
+
+	// Wild kangaroo: from y until it travels past the trap at b + xT
+	limit := new(big.Int).Sub(b, a)
+	limit.Add(limit, xT)
+	xW := new(big.Int)
+	yW := new(big.Int).Set(y)
+	for xW.Cmp(limit) <= 0 {
+		if yW.Cmp(yT) == 0 {
+			// y * g^xW = g^(b+xT)
+			return xW.Sub(xT.Add(xT, b), xW), nil
+		}
+		j := jumps.Index(yW)
+		xW.Add(xW, jumps.Hops[j])
+		yW = grp.Mul(yW, steps[j])
+	}
+	return nil, errors.ErrDiscreteLogNotFound
+}
+
+// RecoverDHKeyKangaroo implements Challenge 58. Bob's key x is below a q
+// too large for the small factors of (p-1)/q to cover, but they still give
+// x = n mod r. Writing x = n + m*r, y*g^-n = (g^r)^m with m in
+// [0, (q-1)/r], a far smaller interval that the kangaroo can search.
+func RecoverDHKeyKangaroo(o *or.Oracle57) (*big.Int, error) {
+	g := o.Group()
+	n, r, err := SubgroupResidues(o, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// g has order q, so g^-n = g^(q-n)
+	gInvN := g.Exp(g.G, new(big.Int).Sub(g.Q, n))
+	y := g.Mul(o.PublicKey(), gInvN)
+	gr := g.Exp(g.G, r)
+	width := new(big.Int).Sub(g.Q, big.NewInt(1))
+	width.Div(width, r)
+
+	m, err := Kangaroo(g, gr, y, big.NewInt(0), width, PowerJumpsFor(width))
+	if err != nil {
+		return nil, err
+	}
+	return m.Mul(m, r).Add(m, n), nil
+}
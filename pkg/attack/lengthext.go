@@ -0,0 +1,104 @@
+package attack
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/md4"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+	"github.com/jonathanlamela/go-cryptopals/pkg/sha1x"
+)
+
+// MDHash describes a Merkle-Damgård hash closely enough to forge extensions:
+// the block size, the byte order of its registers and length field, and a
+// way to resume hashing from a given set of registers.
+type MDHash struct {
+	BlockSize int
+	Order     binary.ByteOrder
+	// FromState resumes the hash from the registers recovered from a digest,
+	// pretending length bytes have already been processed.
+	FromState func(state []uint32, length uint64) hash.Hash
+}
+
+// SHA1 describes pkg/sha1x for the length-extension driver.
+var SHA1 = MDHash{
+	BlockSize: sha1x.BlockSize,
+	Order:     binary.BigEndian,
+	FromState: func(state []uint32, length uint64) hash.Hash {
+		var h [5]uint32
+		copy(h[:], state)
+		return sha1x.NewFromState(h, length)
+	},
+}
+
+// MD4 describes pkg/md4 for the length-extension driver.
+// It differs from SHA1 only in byte order and register count.
+var MD4 = MDHash{
+	BlockSize: md4.BlockSize,
+	Order:     binary.LittleEndian,
+	FromState: func(state []uint32, length uint64) hash.Hash {
+		var h [4]uint32
+		copy(h[:], state)
+		return md4.NewFromState(h, length)
+	},
+}
+
+// Padding returns the glue padding the hash appends to a msgLen-byte message:
+// 0x80, zeros, then the bit length as a 64-bit integer in the hash's byte order.
+func (m MDHash) Padding(msgLen uint64) []byte {
+	padLen := m.BlockSize - int((msgLen+8)%uint64(m.BlockSize))
+	if padLen == 0 {
+		padLen = m.BlockSize
+	}
+	out := make([]byte, padLen+8)
+	out[0] = 0x80
+	m.Order.PutUint64(out[padLen:], msgLen<<3)
+	return out
+}
+
+// Extend forges the digest of secret||msg||glue||suffix from digest, the
+// hash of secret||msg, where totalLen = len(secret)+len(msg).
+// It returns the glue padding and the forged digest.
+func (m MDHash) Extend(digest []byte, totalLen uint64, suffix []byte) ([]byte, []byte) {
+	// The digest is just the final registers serialized in the hash's byte order
+	state := make([]uint32, len(digest)/4)
+	for i := range state {
+		state[i] = m.Order.Uint32(digest[4*i:])
+	}
+	glue := m.Padding(totalLen)
+	h := m.FromState(state, totalLen+uint64(len(glue)))
+	h.Write(suffix)
+	return glue, h.Sum(nil)
+}
+
+// LengthExtension forges a valid secret-prefix MAC for msg||glue||suffix.
+// The secret length is unknown, so every length up to maxKeyLen is tried
+// until verify accepts the forgery.
+func LengthExtension(m MDHash, msg, mac, suffix []byte, maxKeyLen int, verify func(msg, mac []byte) bool) ([]byte, []byte, error) {
+	for keyLen := 0; keyLen <= maxKeyLen; keyLen++ {
+		glue, forged := m.Extend(mac, uint64(keyLen+len(msg)), suffix)
+		forgedMsg := make([]byte, 0, len(msg)+len(glue)+len(suffix))
+		forgedMsg = append(forgedMsg, msg...)
+		forgedMsg = append(forgedMsg, glue...)
+		forgedMsg = append(forgedMsg, suffix...)
+		if verify(forgedMsg, forged) {
+			return forgedMsg, forged, nil
+		}
+	}
+	return nil, nil, errors.ErrLengthExtensionFailed
+}
+
+// ForgeSHA1Admin implements Challenge 29: it extends the oracle's message
+// with ";admin=true" and returns a forged message and MAC the oracle accepts.
+func ForgeSHA1Admin(o *or.Oracle28, maxKeyLen int) ([]byte, []byte, error) {
+	msg, mac := o.Message()
+	return LengthExtension(SHA1, msg, mac, []byte(";admin=true"), maxKeyLen, o.IsAdmin)
+}
+
+// ForgeMD4Admin implements Challenge 30: the same forgery as ForgeSHA1Admin
+// driven through the generic extension code with MD4's parameters.
+func ForgeMD4Admin(o *or.Oracle30, maxKeyLen int) ([]byte, []byte, error) {
+	msg, mac := o.Message()
+	return LengthExtension(MD4, msg, mac, []byte(";admin=true"), maxKeyLen, o.IsAdmin)
+}
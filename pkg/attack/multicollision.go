@@ -0,0 +1,144 @@
+package attack
+
+import (
+	"encoding/binary"
+
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/toymd"
+)
+
+// FindBlockCollision finds two distinct blocks that compress state to the
+// same value by the birthday paradox: hashing random blocks until one output
+// repeats takes about 2^(h.Bits/2) compressions.
+func FindBlockCollision(h *toymd.Hash, state uint32) (a, b []byte, next uint32, err error) {
+	// Blocks are a random base with a counter in the last eight bytes, which
+	// keeps them distinct without drawing fresh randomness for each one
+	base := cu.RandomBytes(toymd.BlockSize)
+	seen := map[uint32]uint64{}
+	limit := uint64(1) << (h.Bits/2 + 8)
+	for i := uint64(0); i < limit; i++ {
+		block := counterBlock(base, i)
+		out := h.Compress(state, block)
+		if j, ok := seen[out]; ok {
+			return counterBlock(base, j), block, out, nil
+		}
+		seen[out] = i
+	}
+	return nil, nil, 0, errors.ErrCollisionNotFound
+}
+
+func counterBlock(base []byte, i uint64) []byte {
+	block := append([]byte(nil), base...)
+	binary.BigEndian.PutUint64(block[toymd.BlockSize-8:], i)
+	return block
+}
+
+// Multicollision is a Joux multicollision. Pairs[i] holds two blocks that
+// take the chain from the same state to the same next state, so all
+// 2^len(Pairs) messages picking one block from each pair reach State.
+// Having equal lengths, they also share a padded hash.
+type Multicollision struct {
+	Pairs [][2][]byte
+	State uint32
+}
+
+// Joux implements the first half of Challenge 52: n successive block
+// collisions from state give 2^n colliding messages for only n times the
+// cost of one collision.
+func Joux(h *toymd.Hash, state uint32, n int) (*Multicollision, error) {
+	m := &Multicollision{State: state}
+	for i := 0; i < n; i++ {
+		if err := m.Extend(h); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Extend appends one more block collision, doubling the message count.
+func (m *Multicollision) Extend(h *toymd.Hash) error {
+	a, b, next, err := FindBlockCollision(h, m.State)
+	if err != nil {
+		return err
+	}
+	m.Pairs = append(m.Pairs, [2][]byte{a, b})
+	m.State = next
+	return nil
+}
+
+// Message returns colliding message number i: bit j of i picks the block
+// of pair j.
+func (m *Multicollision) Message(i uint64) []byte {
+	out := make([]byte, 0, len(m.Pairs)*toymd.BlockSize)
+	for j, pair := range m.Pairs {
+		out = append(out, pair[i>>j&1]...)
+	}
+	return out
+}
+
+// CascadeCollision is a collision in f(x) || g(x), with the cost of finding it.
+type CascadeCollision struct {
+	A, B []byte
+	// Collisions counts the block collisions generated in f
+	Collisions int
+	// FCalls and GCalls count compression function calls in f and g
+	FCalls, GCalls uint64
+}
+
+// maxExtraCollisions bounds how far past Bits/2 the f multicollision grows.
+const maxExtraCollisions = 8
+
+// BreakCascade implements the second half of Challenge 52: f || g is no
+// stronger than g. A Joux multicollision in the cheap f yields 2^(g.Bits/2)
+// messages that all collide in f; by the birthday paradox two of them
+// probably collide in g as well. If none do, one more f collision doubles
+// the pool and the search runs again.
+func BreakCascade(f, g *toymd.Hash) (*CascadeCollision, error) {
+	fStart, gStart := f.Calls(), g.Calls()
+	m, err := Joux(f, f.IV, g.Bits/2)
+	if err != nil {
+		return nil, err
+	}
+	for extra := 0; extra <= maxExtraCollisions; extra++ {
+		if a, b, ok := gCollision(g, m); ok {
+			return &CascadeCollision{
+				A:          m.Message(a),
+				B:          m.Message(b),
+				Collisions: len(m.Pairs),
+				FCalls:     f.Calls() - fStart,
+				GCalls:     g.Calls() - gStart,
+			}, nil
+		}
+		if err := m.Extend(f); err != nil {
+			return nil, err
+		}
+	}
+	return nil, errors.ErrCollisionNotFound
+}
+
+// gCollision walks the multicollision as a binary tree under g, sharing the
+// chain value of every common prefix, and returns the indices of two
+// messages reaching the same g state.
+func gCollision(g *toymd.Hash, m *Multicollision) (a, b uint64, ok bool) {
+	seen := map[uint32]uint64{}
+	var walk func(depth int, state uint32, index uint64) bool
+	walk = func(depth int, state uint32, index uint64) bool {
+		if depth == len(m.Pairs) {
+			if j, dup := seen[state]; dup {
+				a, b = j, index
+				return true
+			}
+			seen[state] = index
+			return false
+		}
+		for bit := uint64(0); bit < 2; bit++ {
+			if walk(depth+1, g.Compress(state, m.Pairs[depth][bit]), index|bit<<depth) {
+				return true
+			}
+		}
+		return false
+	}
+	ok = walk(0, g.IV, 0)
+	return a, b, ok
+}
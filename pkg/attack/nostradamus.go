@@ -0,0 +1,172 @@
+package attack
+
+import (
+	"bytes"
+	"sync"
+
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/toymd"
+)
+
+// diamondLink is one edge of the diamond: the block that moves a state one
+// level closer to the root, and the state it leads to.
+type diamondLink struct {
+	block []byte
+	next  uint32
+}
+
+// Diamond is the funnel of Challenge 54: 2^K leaf states, paired level by
+// level through block collisions, all lead to Root in exactly K blocks.
+type Diamond struct {
+	K      int
+	Leaves []uint32
+	Root   uint32
+
+	// links[i] maps each state of level i (the leaves being level 0) to its edge
+	links []map[uint32]diamondLink
+}
+
+// BuildDiamond builds a diamond of 2^k leaves for h. Each level pairs the
+// states of the previous one and finds a block for each that brings both to
+// a common state; the pairs of a level are independent, so they are spread
+// over workers goroutines. k must be at least 1 and below h.Bits, since
+// there are no more than 2^Bits distinct leaves, otherwise it returns
+// ErrInvalidParameters.
+func BuildDiamond(h *toymd.Hash, k, workers int) (*Diamond, error) {
+	if k < 1 || k >= h.Bits {
+		return nil, errors.ErrInvalidParameters
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	d := &Diamond{K: k}
+
+	// Random leaves are just the images of random blocks, kept distinct
+	base := cu.RandomBytes(toymd.BlockSize)
+	seen := map[uint32]bool{}
+	for i := uint64(0); len(d.Leaves) < 1<<k; i++ {
+		if s := h.Compress(h.IV, counterBlock(base, i)); !seen[s] {
+			seen[s] = true
+			d.Leaves = append(d.Leaves, s)
+		}
+	}
+
+	level := d.Leaves
+	for len(level) > 1 {
+		next := make([]uint32, len(level)/2)
+		links := make([][2]diamondLink, len(next))
+		errs := make([]error, len(next))
+
+		pairs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range pairs {
+					a, b, out, err := findCrossCollision(h, level[2*i], level[2*i+1])
+					next[i], errs[i] = out, err
+					links[i] = [2]diamondLink{{a, out}, {b, out}}
+				}
+			}()
+		}
+		for i := range next {
+			pairs <- i
+		}
+		close(pairs)
+		wg.Wait()
+
+		edges := make(map[uint32]diamondLink, len(level))
+		for i := range next {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			edges[level[2*i]] = links[i][0]
+			edges[level[2*i+1]] = links[i][1]
+		}
+		d.links = append(d.links, edges)
+		level = next
+	}
+	d.Root = level[0]
+	return d, nil
+}
+
+// alignedPrefix pads prefix with spaces to a whole number of blocks.
+func alignedPrefix(prefix []byte) []byte {
+	out := append([]byte(nil), prefix...)
+	for len(out)%toymd.BlockSize != 0 {
+		out = append(out, ' ')
+	}
+	return out
+}
+
+// Prediction returns the hash to commit to: the hash of every message Forge
+// builds from a prefix of prefixLen bytes. The length must be fixed in
+// advance because MD strengthening hashes it into the final block.
+func (d *Diamond) Prediction(h *toymd.Hash, prefixLen int) uint32 {
+	blocks := len(alignedPrefix(make([]byte, prefixLen)))/toymd.BlockSize + 1 + d.K
+	return h.Iterate(d.Root, toymd.Padding(uint64(blocks*toymd.BlockSize)))
+}
+
+// Forge implements Challenge 54: given a prefix written after the
+// prediction was published, it searches, across workers goroutines, for a
+// glue block taking the prefix's chain value to one of the leaves, then
+// follows the diamond to the root. The result starts with prefix (padded
+// with spaces to a block boundary) and hashes to Prediction(len(prefix)).
+func (d *Diamond) Forge(h *toymd.Hash, prefix []byte, workers int) ([]byte, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	padded := alignedPrefix(prefix)
+	state := h.Iterate(h.IV, padded)
+	leaves := make(map[uint32]bool, len(d.Leaves))
+	for _, l := range d.Leaves {
+		leaves[l] = true
+	}
+
+	var (
+		once sync.Once
+		glue []byte
+		leaf uint32
+		done = make(chan struct{})
+		wg   sync.WaitGroup
+	)
+	limit := uint64(1) << (h.Bits + 4 - d.K)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			base := cu.RandomBytes(toymd.BlockSize)
+			for i := uint64(0); i < limit/uint64(workers)+1; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				block := counterBlock(base, i)
+				if s := h.Compress(state, block); leaves[s] {
+					once.Do(func() {
+						glue, leaf = block, s
+						close(done)
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if glue == nil {
+		return nil, errors.ErrCollisionNotFound
+	}
+
+	var out bytes.Buffer
+	out.Write(padded)
+	out.Write(glue)
+	for _, edges := range d.links {
+		link := edges[leaf]
+		out.Write(link.block)
+		leaf = link.next
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,142 @@
+package attack
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+const (
+	// RC4 keystream byte Z16 leans towards 240 and Z32 towards 224 (AlFardan
+	// et al., "On the Security of RC4 in TLS"): about 3.5% and 2% more often
+	// than any other value. Z16 is the stronger one, so it weighs double.
+	z16Bias   = 0xF0
+	z32Bias   = 0xE0
+	z16Weight = 2
+)
+
+// RC4Oracle encrypts request||cookie under a fresh RC4 key on every call;
+// *oracle.Oracle56 implements it.
+type RC4Oracle interface {
+	Encrypt(request []byte) ([]byte, error)
+}
+
+// rc4Counts is how often each value appeared at ciphertext positions 15 and
+// 31 (Z16 and Z32) for one request length.
+type rc4Counts struct {
+	z16, z32 [256]int
+}
+
+// countRC4Biased sends a request of prefixLen bytes samples times, split
+// over workers goroutines, and tallies the ciphertext bytes encrypted with
+// Z16 and Z32. Positions past the end of the ciphertext are not counted.
+func countRC4Biased(o RC4Oracle, prefixLen, samples, workers int) (*rc4Counts, error) {
+	request := bytes.Repeat([]byte{'A'}, prefixLen)
+	counts := make([]rc4Counts, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			c := &counts[w]
+			for n := w; n < samples; n += workers {
+				ct, err := o.Encrypt(request)
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				if len(ct) > 15 {
+					c.z16[ct[15]]++
+				}
+				if len(ct) > 31 {
+					c.z32[ct[31]]++
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	total := &rc4Counts{}
+	for w := range counts {
+		if errs[w] != nil {
+			return nil, errs[w]
+		}
+		for v := 0; v < 256; v++ {
+			total.z16[v] += counts[w].z16[v]
+			total.z32[v] += counts[w].z32[v]
+		}
+	}
+	return total, nil
+}
+
+// RecoverRC4Cookie implements Challenge 56. The oracle encrypts
+// request||cookie under a fresh RC4 key each time, so a cookie byte shifted
+// to position 15 or 31 is XORed with the biased keystream byte Z16 or Z32:
+// the most frequent ciphertext value there, XORed with the bias, is the
+// cookie byte. Request lengths 15-i and 31-i put byte i under Z16 and Z32
+// respectively, and both tallies are added up when available.
+//
+// samples is the number of encryptions per request length: 2^23 is plenty
+// for bytes under Z16, while those past byte 15 only have Z32 and want
+// 2^24 or more. indices selects the cookie bytes to recover
+// (all of them when nil) and the others are left zero, which lets tests
+// recover only a couple of bytes. Only the first 32 bytes can be reached.
+func RecoverRC4Cookie(o RC4Oracle, samples, workers int, indices []int) ([]byte, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	ct, err := o.Encrypt(nil)
+	if err != nil {
+		return nil, err
+	}
+	cookie := make([]byte, len(ct))
+	if indices == nil {
+		for i := range cookie {
+			indices = append(indices, i)
+		}
+	}
+
+	tallies := map[int]*rc4Counts{}
+	tally := func(prefixLen int) (*rc4Counts, error) {
+		if c, ok := tallies[prefixLen]; ok {
+			return c, nil
+		}
+		c, err := countRC4Biased(o, prefixLen, samples, workers)
+		tallies[prefixLen] = c
+		return c, err
+	}
+
+	for _, i := range indices {
+		if i < 0 || i >= len(cookie) || i > 31 {
+			return nil, errors.ErrInvalidLength
+		}
+		var score [256]int
+		if i <= 15 {
+			c, err := tally(15 - i)
+			if err != nil {
+				return nil, err
+			}
+			for v := range score {
+				score[v] += z16Weight * c.z16[v^z16Bias]
+			}
+		}
+		c, err := tally(31 - i)
+		if err != nil {
+			return nil, err
+		}
+		for v := range score {
+			score[v] += c.z32[v^z32Bias]
+		}
+
+		best := 0
+		for v := range score {
+			if score[v] > score[best] {
+				best = v
+			}
+		}
+		cookie[i] = byte(best)
+	}
+	return cookie, nil
+}
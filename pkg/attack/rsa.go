@@ -0,0 +1,134 @@
+package attack
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+// Broadcast is one capture of the same message encrypted to a different e=3 key.
+type Broadcast struct {
+	C *big.Int
+	N *big.Int
+}
+
+// HastadBroadcast implements Challenge 40: given the same plaintext
+// encrypted under three e=3 keys, CRT yields m^3 modulo N1*N2*N3. Since
+// m < Ni, m^3 is smaller than that product, so the result is m^3 exactly
+// and an integer cube root recovers m.
+func HastadBroadcast(captures [3]Broadcast) (*big.Int, error) {
+	residues := make([]*big.Int, len(captures))
+	moduli := make([]*big.Int, len(captures))
+	for i, c := range captures {
+		residues[i], moduli[i] = c.C, c.N
+	}
+	cubed, err := rsa.CRT(residues, moduli)
+	if err != nil {
+		return nil, err
+	}
+	m, exact := rsa.NthRoot(cubed, 3)
+	if !exact {
+		return nil, errors.ErrNotPerfectPower
+	}
+	return m, nil
+}
+
+// RecoverUnpadded implements Challenge 41: it recovers the plaintext of c
+// from a server that refuses to decrypt c twice. Textbook RSA is
+// multiplicative, so the blinded C' = S^e * C decrypts to S * P, and
+// multiplying by S^-1 mod N removes the blind.
+func RecoverUnpadded(o *or.Oracle41, c []byte) ([]byte, error) {
+	pub := o.PublicKey()
+	S, err := rand.Int(rand.Reader, new(big.Int).Sub(pub.N, big.NewInt(2)))
+	if err != nil {
+		return nil, err
+	}
+	S.Add(S, big.NewInt(2))
+	sInv, err := rsa.InvMod(S, pub.N)
+	if err != nil {
+		// S shares a factor with N; astronomically unlikely, but then gcd factors N
+		return nil, err
+	}
+
+	blinded := pub.Encrypt(S)
+	blinded.Mul(blinded, new(big.Int).SetBytes(c)).Mod(blinded, pub.N)
+	p, err := o.Decrypt(blinded.FillBytes(make([]byte, pub.Size())))
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(big.Int).SetBytes(p)
+	m.Mul(m, sInv).Mod(m, pub.N)
+	return m.Bytes(), nil
+}
+
+// ForgePKCS1Signature implements Challenge 42: Bleichenbacher's e=3
+// signature forgery. It lays out 00 01 FF 00 DigestInfo at the top of the
+// block, leaves the rest as garbage and takes the cube root rounded up.
+// Cubing the result lands within 3s^2 of the target, which only disturbs
+// the garbage as long as enough of the block is left over for it.
+func ForgePKCS1Signature(pub *rsa.PublicKey, h crypto.Hash, digest []byte) ([]byte, error) {
+	if pub.E.Cmp(big.NewInt(3)) != 0 {
+		return nil, errors.ErrForgeryFailed
+	}
+	info, err := rsa.DigestInfo(h, digest)
+	if err != nil {
+		return nil, err
+	}
+	prefix := append([]byte{0x00, 0x01, 0xff, 0x00}, info...)
+	block := make([]byte, pub.Size())
+	copy(block, prefix)
+
+	s, exact := rsa.NthRoot(new(big.Int).SetBytes(block), 3)
+	if !exact {
+		s.Add(s, big.NewInt(1))
+	}
+	cube := new(big.Int).Exp(s, big.NewInt(3), nil)
+	if cube.Cmp(pub.N) >= 0 || !bytes.HasPrefix(cube.FillBytes(make([]byte, pub.Size())), prefix) {
+		return nil, errors.ErrForgeryFailed
+	}
+	return s.FillBytes(make([]byte, pub.Size())), nil
+}
+
+// RecoverFromParity implements Challenge 46. Multiplying c by 2^e doubles
+// the plaintext mod N; since N is odd, 2m mod N is even exactly when 2m did
+// not wrap, i.e. when m < N/2. Each query halves the interval holding m.
+// The bounds are kept exact as a*N/2^i and (a+1)*N/2^i, so no rounding
+// ever drops the last byte. progress, if non-nil, receives the upper bound
+// after every step.
+func RecoverFromParity(o *or.Oracle46, c []byte, progress func(upper []byte)) ([]byte, error) {
+	pub := o.PublicKey()
+	double := pub.Encrypt(big.NewInt(2))
+	ct := new(big.Int).SetBytes(c)
+
+	k := pub.N.BitLen()
+	a := new(big.Int)
+	upper := new(big.Int)
+	for i := 1; i <= k; i++ {
+		ct.Mul(ct, double).Mod(ct, pub.N)
+		even, err := o.IsEven(ct.FillBytes(make([]byte, pub.Size())))
+		if err != nil {
+			return nil, err
+		}
+		a.Lsh(a, 1)
+		if !even {
+			a.Add(a, big.NewInt(1))
+		}
+		if progress != nil {
+			upper.Add(a, big.NewInt(1))
+			upper.Mul(upper, pub.N).Rsh(upper, uint(i))
+			progress(upper.Bytes())
+		}
+	}
+
+	// m lies in [a*N/2^k, (a+1)*N/2^k), an interval narrower than 1
+	m := new(big.Int).Mul(a, pub.N)
+	m.Add(m, new(big.Int).Lsh(big.NewInt(1), uint(k)))
+	m.Sub(m, big.NewInt(1)).Rsh(m, uint(k))
+	return m.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package attack
+
+import (
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/toymd"
+)
+
+// ExpandableMessage is a Kelsey-Schneier expandable message: a set of
+// messages of every length from K to K+2^K-1 blocks that all lead from
+// the same starting state to State.
+// Pieces[j] holds a one-block message and a 2^(K-1-j)+1 block message that
+// take the chain to the same next state; choosing the short or long form
+// of each piece selects the length.
+type ExpandableMessage struct {
+	K      int
+	Pieces [][2][]byte
+	State  uint32
+}
+
+// NewExpandableMessage builds a k-piece expandable message from state. Each
+// piece is a birthday collision between a single block and n dummy blocks
+// followed by one more block, costing about 2^(h.Bits/2) + n compressions.
+func NewExpandableMessage(h *toymd.Hash, state uint32, k int) (*ExpandableMessage, error) {
+	e := &ExpandableMessage{K: k, State: state}
+	dummy := make([]byte, toymd.BlockSize)
+	for j := 0; j < k; j++ {
+		n := 1 << (k - 1 - j)
+		prefix := make([]byte, 0, n*toymd.BlockSize)
+		for i := 0; i < n; i++ {
+			prefix = append(prefix, dummy...)
+		}
+		short, last, next, err := findCrossCollision(h, e.State, h.Iterate(e.State, prefix))
+		if err != nil {
+			return nil, err
+		}
+		e.Pieces = append(e.Pieces, [2][]byte{short, append(prefix, last...)})
+		e.State = next
+	}
+	return e, nil
+}
+
+// findCrossCollision finds blocks a and b with Compress(s, a) == Compress(t, b)
+// by growing a table on each side until they meet.
+func findCrossCollision(h *toymd.Hash, s, t uint32) (a, b []byte, next uint32, err error) {
+	baseA, baseB := cu.RandomBytes(toymd.BlockSize), cu.RandomBytes(toymd.BlockSize)
+	fromS, fromT := map[uint32]uint64{}, map[uint32]uint64{}
+	limit := uint64(1) << (h.Bits/2 + 8)
+	for i := uint64(0); i < limit; i++ {
+		outA := h.Compress(s, counterBlock(baseA, i))
+		if j, ok := fromT[outA]; ok {
+			return counterBlock(baseA, i), counterBlock(baseB, j), outA, nil
+		}
+		fromS[outA] = i
+
+		outB := h.Compress(t, counterBlock(baseB, i))
+		if j, ok := fromS[outB]; ok {
+			return counterBlock(baseA, j), counterBlock(baseB, i), outB, nil
+		}
+		fromT[outB] = i
+	}
+	return nil, nil, 0, errors.ErrCollisionNotFound
+}
+
+// Produce returns the member of the expandable message that is blocks
+// blocks long, K <= blocks <= K+2^K-1.
+func (e *ExpandableMessage) Produce(blocks int) ([]byte, error) {
+	extra := blocks - e.K
+	if extra < 0 || extra >= 1<<e.K {
+		return nil, errors.ErrInvalidLength
+	}
+	var out []byte
+	for j, piece := range e.Pieces {
+		// The long form of piece j adds 2^(K-1-j) blocks
+		out = append(out, piece[extra>>(e.K-1-j)&1]...)
+	}
+	return out, nil
+}
+
+// IntermediateStates maps every chain value reached while hashing the
+// block-aligned msg from the IV to the number of blocks consumed so far.
+func IntermediateStates(h *toymd.Hash, msg []byte) map[uint32]int {
+	states := map[uint32]int{}
+	state := h.IV
+	for i := 0; i+toymd.BlockSize <= len(msg); i += toymd.BlockSize {
+		state = h.Compress(state, msg[i:i+toymd.BlockSize])
+		states[state] = i/toymd.BlockSize + 1
+	}
+	return states
+}
+
+// SecondPreimage implements Challenge 53: it returns a message different
+// from msg, of the same length, with the same toy hash. msg must be a
+// whole number of blocks, at least 2^k+1 of them.
+//
+// An expandable message reaches some state; a random bridge block from
+// there is searched until it lands on one of msg's intermediate states,
+// after i blocks with k < i <= 2^k+k. The expandable message is then sized
+// to i-1 blocks and msg's remaining blocks are appended. The total length,
+// hence the padding, matches msg, so the hashes do too.
+func SecondPreimage(h *toymd.Hash, msg []byte, k int) ([]byte, error) {
+	if len(msg)%toymd.BlockSize != 0 || len(msg)/toymd.BlockSize <= 1<<k {
+		return nil, errors.ErrInvalidLength
+	}
+	e, err := NewExpandableMessage(h, h.IV, k)
+	if err != nil {
+		return nil, err
+	}
+	states := IntermediateStates(h, msg)
+
+	base := cu.RandomBytes(toymd.BlockSize)
+	limit := uint64(1) << (h.Bits + 4 - k)
+	for i := uint64(0); i < limit; i++ {
+		bridge := counterBlock(base, i)
+		blocks, ok := states[h.Compress(e.State, bridge)]
+		if !ok || blocks <= k || blocks > k+1<<k {
+			continue
+		}
+		prefix, err := e.Produce(blocks - 1)
+		if err != nil {
+			return nil, err
+		}
+		return append(append(prefix, bridge...), msg[blocks*toymd.BlockSize:]...), nil
+	}
+	return nil, errors.ErrCollisionNotFound
+}
@@ -0,0 +1,66 @@
+package attack
+
+import (
+	"crypto/hmac"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/srp"
+)
+
+// LoginWithZeroKey implements Challenge 37: it logs in as email without the
+// password by sending A = multiple*N. The server then computes
+// S = (A * v^u)^b = 0 mod N, a session key the attacker knows too.
+// multiple 0, 1 and 2 give A = 0, N and 2N.
+func LoginWithZeroKey(t srp.Transport, group *dh.Group, email string, multiple int64) error {
+	A := new(big.Int).Mul(group.P, big.NewInt(multiple))
+	ch, err := t.Hello(email, A)
+	if err != nil {
+		return err
+	}
+	key := srp.SessionKey(big.NewInt(0))
+	return t.Login(email, srp.Proof(key, ch.Salt))
+}
+
+// SimpleSRPMITM implements Challenge 38: it poses as a simplified-SRP
+// server, answering with b = 1, B = g and u = 1. The client then computes
+// S = g^(a + x) = A * g^x, so the captured proof can be checked offline
+// against any password guess.
+type SimpleSRPMITM struct {
+	Group *dh.Group
+	Salt  []byte
+
+	email string
+	a     *big.Int
+	mac   []byte
+}
+
+// Hello records the client's A and returns the attacker-chosen parameters.
+func (m *SimpleSRPMITM) Hello(email string, A *big.Int) (*srp.Challenge, error) {
+	m.email, m.a = email, new(big.Int).Set(A)
+	return &srp.Challenge{Salt: m.Salt, B: new(big.Int).Set(m.Group.G), U: big.NewInt(1)}, nil
+}
+
+// Login records the proof. It always refuses, as it cannot check it online.
+func (m *SimpleSRPMITM) Login(email string, mac []byte) error {
+	m.mac = append([]byte(nil), mac...)
+	return errors.ErrSRPLoginFailed
+}
+
+// CrackPassword runs the offline dictionary attack on the captured proof.
+func (m *SimpleSRPMITM) CrackPassword(dictionary []string) (string, error) {
+	if m.a == nil || m.mac == nil {
+		return "", errors.ErrPasswordNotFound
+	}
+	N := m.Group.P
+	for _, guess := range dictionary {
+		x := srp.HashInt(m.Salt, []byte(guess))
+		S := new(big.Int).Mul(m.a, dh.ModExp(m.Group.G, x, N))
+		S.Mod(S, N)
+		if hmac.Equal(srp.Proof(srp.SessionKey(S), m.Salt), m.mac) {
+			return guess, nil
+		}
+	}
+	return "", errors.ErrPasswordNotFound
+}
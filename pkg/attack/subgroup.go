@@ -0,0 +1,103 @@
+package attack
+
+import (
+	"crypto/hmac"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	or "github.com/jonathanlamela/go-cryptopals/pkg/oracle"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+// subgroupFactorBound caps the factors of (p-1)/q worth attacking: each
+// factor r costs up to r MAC checks.
+const subgroupFactorBound = 1 << 16
+
+// SmallFactors returns the distinct primes below bound that divide n, in
+// increasing order, by trial division.
+func SmallFactors(n *big.Int, bound int64) []*big.Int {
+	rest := new(big.Int).Set(n)
+	var factors []*big.Int
+	r, mod := new(big.Int), new(big.Int)
+	for d := int64(2); d < bound; d++ {
+		r.SetInt64(d)
+		if mod.Mod(rest, r).Sign() != 0 {
+			continue
+		}
+		// d is prime here: its own factors were divided out earlier
+		factors = append(factors, big.NewInt(d))
+		for mod.Mod(rest, r).Sign() == 0 {
+			rest.Div(rest, r)
+		}
+	}
+	return factors
+}
+
+// matchSubgroupMAC finds b in [0, r) with MAC(h^b) = mac, h of order r.
+// Bob's shared secret h^x is h^(x mod r), so b is x mod r.
+func matchSubgroupMAC(g *dh.Group, h, r *big.Int, msg, mac []byte) (*big.Int, error) {
+	k := big.NewInt(1)
+	for b := int64(0); b < r.Int64(); b++ {
+		if hmac.Equal(or.MAC57(k, msg), mac) {
+			return big.NewInt(b), nil
+		}
+		k.Mul(k, h).Mod(k, g.P)
+	}
+	return nil, errors.ErrKeyRecoveryFailed
+}
+
+// SubgroupResidues is the small-subgroup confinement step of Challenges
+// 57-58. For each prime r below 2^16 dividing (p-1)/q it sends Bob an
+// element of order r and recovers x mod r from his MAC by brute force,
+// stopping once the product of the moduli exceeds limit (or the factors
+// run out, when limit is nil or too large). The residues are recombined by
+// CRT into x mod m, returned with m.
+func SubgroupResidues(o *or.Oracle57, limit *big.Int) (*big.Int, *big.Int, error) {
+	g := o.Group()
+	j := new(big.Int).Sub(g.P, big.NewInt(1))
+	j.Div(j, g.Q)
+
+	var residues, moduli []*big.Int
+	m := big.NewInt(1)
+	for _, r := range SmallFactors(j, subgroupFactorBound) {
+		if limit != nil && m.Cmp(limit) > 0 {
+			break
+		}
+		h, err := g.ElementOfOrder(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		msg, mac := o.Respond(h)
+		b, err := matchSubgroupMAC(g, h, r, msg, mac)
+		if err != nil {
+			return nil, nil, err
+		}
+		residues = append(residues, b)
+		moduli = append(moduli, r)
+		m.Mul(m, r)
+	}
+	if len(moduli) == 0 {
+		return nil, nil, errors.ErrKeyRecoveryFailed
+	}
+	x, err := rsa.CRT(residues, moduli)
+	if err != nil {
+		return nil, nil, err
+	}
+	return x, m, nil
+}
+
+// RecoverDHKeySubgroup implements Challenge 57, Pohlig-Hellman against an
+// unvalidated DH peer: the small factors of (p-1)/q multiply past q, so
+// x mod their product is x itself.
+func RecoverDHKeySubgroup(o *or.Oracle57) (*big.Int, error) {
+	q := o.Group().Q
+	x, m, err := SubgroupResidues(o, q)
+	if err != nil {
+		return nil, err
+	}
+	if m.Cmp(q) <= 0 {
+		return nil, errors.ErrKeyRecoveryFailed
+	}
+	return x, nil
+}
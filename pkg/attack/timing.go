@@ -0,0 +1,279 @@
+package attack
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// TimingOptions tunes the HMAC timing attack.
+// Zero values select defaults suitable for the 5ms server of Challenge 31.
+type TimingOptions struct {
+	// SigLen is the signature length in bytes (default 20).
+	SigLen int
+	// FirstPass is the number of timings per byte value used to build the
+	// shortlist (default 1). Raise it when sleeps are too short to be precise.
+	FirstPass int
+	// Samples is the number of timings taken per shortlisted candidate (default 5).
+	Samples int
+	// Candidates is how many bytes survive the single-sample first pass (default 8).
+	Candidates int
+	// Stat reduces a candidate's timings to one value (default Median).
+	Stat func([]time.Duration) time.Duration
+}
+
+// Median returns the median of ds. Unlike the mean it ignores scheduler hiccups.
+func Median(ds []time.Duration) time.Duration {
+	s := append([]time.Duration(nil), ds...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	if len(s) == 0 {
+		return 0
+	}
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
+
+// TrimmedMean returns a statistic averaging ds after discarding the given
+// fraction of samples from each end. It keeps more information than the
+// median while still rejecting outliers, which helps with sub-ms delays.
+// Fractions outside [0, 0.5) are clamped: 0.5 and above is the median.
+func TrimmedMean(fraction float64) func([]time.Duration) time.Duration {
+	fraction = max(fraction, 0)
+	return func(ds []time.Duration) time.Duration {
+		s := append([]time.Duration(nil), ds...)
+		sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+		cut := int(float64(len(s)) * fraction)
+		if 2*cut >= len(s) {
+			return Median(ds)
+		}
+		s = s[cut : len(s)-cut]
+		if len(s) == 0 {
+			return Median(ds)
+		}
+		var sum time.Duration
+		for _, d := range s {
+			sum += d
+		}
+		return sum / time.Duration(len(s))
+	}
+}
+
+// timingProbe sends signature guesses for one file to the server.
+type timingProbe struct {
+	client  *http.Client
+	baseURL string
+	file    string
+	// step is the delay one more matching byte adds, learned from the
+	// first byte where jitter is smallest; zero until then
+	step time.Duration
+}
+
+// query sends sig and returns the response status and round-trip time.
+func (p *timingProbe) query(sig []byte) (int, time.Duration, error) {
+	q := url.Values{}
+	q.Set("file", p.file)
+	q.Set("signature", hex.EncodeToString(sig))
+	start := time.Now()
+	resp, err := p.client.Get(p.baseURL + "?" + q.Encode())
+	elapsed := time.Since(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, elapsed, nil
+}
+
+// shortlist times every value of sig[i] in the given number of rounds and
+// returns the n values with the highest statistic.
+func (p *timingProbe) shortlist(sig []byte, i, rounds, n int, stat func([]time.Duration) time.Duration) ([]int, error) {
+	samples := make([][]time.Duration, 256)
+	for r := 0; r < rounds; r++ {
+		for c := 0; c < 256; c++ {
+			sig[i] = byte(c)
+			_, d, err := p.query(sig)
+			if err != nil {
+				return nil, err
+			}
+			samples[c] = append(samples[c], d)
+		}
+	}
+	first := make([]time.Duration, 256)
+	for c := range first {
+		first[c] = stat(samples[c])
+	}
+	out := make([]int, 256)
+	for c := range out {
+		out[c] = c
+	}
+	sort.Slice(out, func(a, b int) bool { return first[out[a]] > first[out[b]] })
+	return out[:min(n, len(out))], nil
+}
+
+// RecoverHMACByTiming implements Challenges 31-32: it recovers the HMAC of
+// file from a server using an early-exit comparison, one byte at a time.
+//
+// For each position every byte value is timed FirstPass times, the slowest
+// Candidates are re-timed Samples times in interleaved rounds, and the one
+// with the highest statistic wins if it stands out, by about the delay the
+// first byte showed, in two refinements in a row. Ambiguous positions are
+// re-sampled with twice as many rounds, up to eight times, and then rebuilt
+// from a fresh shortlist.
+// A position that still has no clear winner sends the attack back to the
+// byte before it, or measures the first byte again.
+// The last byte is found by status code rather than timing.
+func RecoverHMACByTiming(client *http.Client, baseURL, file string, opts TimingOptions) ([]byte, error) {
+	if opts.SigLen == 0 {
+		opts.SigLen = 20
+	}
+	if opts.FirstPass == 0 {
+		opts.FirstPass = 1
+	}
+	if opts.Samples == 0 {
+		opts.Samples = 5
+	}
+	if opts.Candidates == 0 {
+		opts.Candidates = 8
+	}
+	if opts.Stat == nil {
+		opts.Stat = Median
+	}
+	p := &timingProbe{client: client, baseURL: baseURL, file: file}
+	sig := make([]byte, opts.SigLen)
+
+	backtracks := 0
+	for i := 0; i < opts.SigLen; {
+		var c int
+		var ok bool
+		var err error
+		if i == opts.SigLen-1 {
+			c, ok, err = p.lastByte(sig)
+		} else {
+			c, ok, err = p.timeByte(sig, i, opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			sig[i] = byte(c)
+			i++
+			continue
+		}
+		if backtracks == maxBacktracks {
+			return nil, errors.ErrTimingAttackFailed
+		}
+		backtracks++
+		// Nothing stood out here, so the byte before was most likely wrong;
+		// with no byte before, the first position is simply measured again
+		if i > 0 {
+			i--
+		}
+	}
+	return sig, nil
+}
+
+// maxBacktracks bounds how often RecoverHMACByTiming revisits a byte or
+// re-measures the first one.
+const maxBacktracks = 8
+
+// timeByte picks the value of sig[i] that makes the server slowest and
+// reports whether it clearly stood out from the other candidates.
+func (p *timingProbe) timeByte(sig []byte, i int, opts TimingOptions) (int, bool, error) {
+	// A winner that never stands out means the right byte probably
+	// missed the shortlist, so the whole position is retried.
+	best, distinct := 0, false
+	for attempt := 0; !distinct && attempt < 3; attempt++ {
+		shortlist, err := p.shortlist(sig, i, opts.FirstPass, opts.Candidates, opts.Stat)
+		if err != nil {
+			return 0, false, err
+		}
+		for rounds := opts.Samples; !distinct && rounds <= 8*opts.Samples; rounds *= 2 {
+			var lead time.Duration
+			best, lead, distinct, err = p.refine(sig, i, shortlist, rounds, opts.Stat)
+			if err != nil {
+				return 0, false, err
+			}
+			if !distinct {
+				continue
+			}
+			// A burst of jitter can single out a wrong candidate once, but
+			// rarely twice: only a winner that stands out again on fresh
+			// timings is kept, which is far cheaper than backtracking later
+			again, _, confirmed, err := p.refine(sig, i, shortlist, rounds, opts.Stat)
+			if err != nil {
+				return 0, false, err
+			}
+			distinct = confirmed && again == best
+			if distinct && i == 0 {
+				p.step = lead
+			}
+		}
+	}
+	return best, distinct, nil
+}
+
+// refine times the shortlisted values of sig[i] in interleaved rounds, so
+// drift hits every candidate equally, and picks the slowest.
+func (p *timingProbe) refine(sig []byte, i int, shortlist []int, rounds int, stat func([]time.Duration) time.Duration) (int, time.Duration, bool, error) {
+	samples := make([][]time.Duration, len(shortlist))
+	for r := 0; r < rounds; r++ {
+		for j, c := range shortlist {
+			sig[i] = byte(c)
+			_, d, err := p.query(sig)
+			if err != nil {
+				return 0, 0, false, err
+			}
+			samples[j] = append(samples[j], d)
+		}
+	}
+	best, lead, distinct := pickSlowest(shortlist, samples, stat, p.step)
+	return best, lead, distinct, nil
+}
+
+// lastByte finds the final byte by status code: the server tells us
+// outright when the signature is right.
+func (p *timingProbe) lastByte(sig []byte) (int, bool, error) {
+	last := len(sig) - 1
+	for c := 0; c < 256; c++ {
+		sig[last] = byte(c)
+		status, _, err := p.query(sig)
+		if err != nil {
+			return 0, false, err
+		}
+		if status == http.StatusOK {
+			return c, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// pickSlowest returns the candidate with the highest statistic, its lead over
+// the runner-up and whether it clearly stands out: the lead must be at least
+// twice the spread of everything else, which a lucky outlier rarely
+// achieves. With step known the lead must also lie between half and twice
+// of it, since the right byte adds one step: jitter grows with every byte
+// compared, and deep in the signature it easily spreads a few candidates
+// apart by less, or a burst of it lifts one by far more.
+func pickSlowest(cands []int, samples [][]time.Duration, stat func([]time.Duration) time.Duration, step time.Duration) (int, time.Duration, bool) {
+	stats := make([]time.Duration, len(cands))
+	order := make([]int, len(cands))
+	for j := range cands {
+		stats[j] = stat(samples[j])
+		order[j] = j
+	}
+	sort.Slice(order, func(a, b int) bool { return stats[order[a]] > stats[order[b]] })
+	if len(order) < 3 {
+		return cands[order[0]], 0, true
+	}
+	best, second, lowest := stats[order[0]], stats[order[1]], stats[order[len(order)-1]]
+	lead := best - second
+	if step > 0 && (2*lead < step || lead > 2*step) {
+		return cands[order[0]], lead, false
+	}
+	return cands[order[0]], lead, lead >= 2*(second-lowest)
+}
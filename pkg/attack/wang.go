@@ -0,0 +1,245 @@
+package attack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/md4"
+)
+
+// md4IV is the standard MD4 initialization vector as a0, d0, c0, b0, the
+// order in which the chaining variables are updated.
+var md4IV = [4]uint32{0x67452301, 0x10325476, 0x98BADCFE, 0xEFCDAB89}
+
+// condKind says what a sufficient condition requires of a bit.
+type condKind int
+
+const (
+	condZero condKind = iota
+	condOne
+	// condEq asks for the same bit of the chaining value `prev` steps back
+	condEq
+	// condNeq asks for the opposite bit of the chaining value `prev` steps back
+	condNeq
+)
+
+// cond is one sufficient condition on a chaining value: Wang et al. write
+// them as e.g. "d1,8 = a1,8" with bits counted from 1; bit here counts from 0.
+type cond struct {
+	bit  uint
+	kind condKind
+	prev int
+}
+
+func zero(bit uint) cond            { return cond{bit: bit - 1, kind: condZero} }
+func one(bit uint) cond             { return cond{bit: bit - 1, kind: condOne} }
+func eq(bit uint, p int) cond       { return cond{bit: bit - 1, kind: condEq, prev: p} }
+func neq(bit uint, p int) cond      { return cond{bit: bit - 1, kind: condNeq, prev: p} }
+func bitOf(x uint32, b uint) uint32 { return x >> b & 1 }
+
+// wangConditions lists, for each of the first 23 MD4 steps (a1, d1, c1, b1,
+// a2, ... c6), the sufficient conditions of Wang et al., "Cryptanalysis of
+// the Hash Functions MD4 and RIPEMD" (Table 6). Round 1 (steps 0-15) is
+// enforced exactly; round 2 is corrected where the message modifications
+// below allow it and left to chance otherwise.
+var wangConditions = [][]cond{
+	// a1 .. b1
+	{eq(7, 1)},
+	{zero(7), eq(8, 1), eq(11, 1)},
+	{one(7), one(8), zero(11), eq(26, 1)},
+	{one(7), zero(8), zero(11), zero(26)},
+	// a2 .. b2
+	{one(8), one(11), zero(26), eq(14, 1)},
+	{zero(14), eq(19, 1), eq(20, 1), eq(21, 1), eq(22, 1), one(26)},
+	{eq(13, 1), zero(14), eq(15, 1), zero(19), zero(20), one(21), zero(22)},
+	{one(13), one(14), zero(15), eq(17, 1), zero(19), zero(20), zero(21), zero(22)},
+	// a3 .. b3
+	{one(13), one(14), one(15), zero(17), zero(19), zero(20), zero(21), eq(23, 1), one(22), eq(26, 1)},
+	{one(13), one(14), one(15), zero(17), zero(20), one(21), one(22), zero(23), one(26), eq(30, 1)},
+	{one(17), zero(20), zero(21), zero(22), zero(23), zero(26), one(30), eq(32, 1)},
+	{zero(20), one(21), one(22), eq(23, 1), one(26), zero(30), zero(32)},
+	// a4 .. b4
+	{zero(23), zero(26), eq(27, 1), eq(29, 1), one(30), zero(32)},
+	{zero(23), zero(26), one(27), one(29), zero(30), one(32)},
+	{eq(19, 1), one(23), one(26), zero(27), zero(29), zero(30)},
+	{zero(19), one(26), one(27), one(29), zero(30)},
+	// a5 .. c6; the a5 row follows the usual errata (the paper compares with c4)
+	{eq(19, 2), eq(26, 1), eq(27, 1), eq(29, 1), eq(32, 1)},
+	{eq(19, 1), eq(26, 2), eq(27, 2), eq(29, 2), eq(32, 2)},
+	{eq(26, 1), eq(27, 1), eq(29, 1), eq(30, 1), eq(32, 1)},
+	{eq(29, 1), one(30), zero(32)},
+	{one(29), one(32)},
+	{eq(29, 2)},
+	{eq(29, 1), neq(30, 1), neq(32, 1)},
+}
+
+// md4State tracks the message words and the chaining values of a candidate
+// block: q[i+4] is the value computed at step i, q[0..3] the IV.
+type md4State struct {
+	m [16]uint32
+	q [4 + 23]uint32
+}
+
+var round1Shifts = [4]int{3, 7, 11, 19}
+var round2Shifts = [4]int{3, 5, 9, 13}
+
+func md4F(x, y, z uint32) uint32 { return (x & y) | (^x & z) }
+func md4G(x, y, z uint32) uint32 { return (x & y) | (x & z) | (y & z) }
+
+// satisfies reports whether the value of step i meets condition c.
+func (s *md4State) satisfies(i int, c cond) bool {
+	v := bitOf(s.q[i+4], c.bit)
+	switch c.kind {
+	case condZero:
+		return v == 0
+	case condOne:
+		return v == 1
+	case condEq:
+		return v == bitOf(s.q[i+4-c.prev], c.bit)
+	default:
+		return v != bitOf(s.q[i+4-c.prev], c.bit)
+	}
+}
+
+// force rewrites the value of step i so that all its conditions hold.
+func (s *md4State) force(i int) {
+	v := &s.q[i+4]
+	for _, c := range wangConditions[i] {
+		var want uint32
+		switch c.kind {
+		case condZero:
+			want = 0
+		case condOne:
+			want = 1
+		case condEq:
+			want = bitOf(s.q[i+4-c.prev], c.bit)
+		default:
+			want = bitOf(s.q[i+4-c.prev], c.bit) ^ 1
+		}
+		*v = *v&^(1<<c.bit) | want<<c.bit
+	}
+}
+
+// step1 computes round-1 step i from the current message words.
+func (s *md4State) step1(i int) uint32 {
+	q := s.q[i : i+4]
+	return bits.RotateLeft32(q[0]+md4F(q[3], q[2], q[1])+s.m[i], round1Shifts[i%4])
+}
+
+// solve1 picks m[i] so that round-1 step i produces the stored value.
+func (s *md4State) solve1(i int) {
+	q := s.q[i : i+5]
+	s.m[i] = bits.RotateLeft32(q[4], -round1Shifts[i%4]) - q[0] - md4F(q[3], q[2], q[1])
+}
+
+// step2 computes round-2 step i (16 <= i < 32) from the current words.
+func (s *md4State) step2(i int) uint32 {
+	j := i - 16
+	q := s.q[i : i+4]
+	k := (j%4)*4 + j/4
+	return bits.RotateLeft32(q[0]+md4G(q[3], q[2], q[1])+s.m[k]+0x5A827999, round2Shifts[j%4])
+}
+
+// modify flips bit b of the round-1 value at step i and re-solves the
+// message words of that step and the next four, so that every other
+// round-1 value stays the same. Only the words of steps i..i+4 change.
+func (s *md4State) modify(i int, b uint) {
+	s.q[i+4] ^= 1 << b
+	for j := i; j < i+5 && j < 16; j++ {
+		s.solve1(j)
+	}
+}
+
+// fixRound2 recomputes round-2 step i, 16 <= i < 23, correcting what the
+// message modifications can reach. A word used at round-2 step i is also
+// used at round-1 step r = 4*(i-16): flipping bit b of that round-1 value
+// shifts the round-2 sum by the same amount, landing on bit
+// b - s1 + s2 of the round-2 value.
+func (s *md4State) fixRound2(i int, fixable map[uint]bool) {
+	r := 4 * (i - 16)
+	s1, s2 := uint(round1Shifts[0]), uint(round2Shifts[i-16])
+	s.q[i+4] = s.step2(i)
+	for _, c := range wangConditions[i] {
+		if !fixable[c.bit] || s.satisfies(i, c) {
+			continue
+		}
+		s.modify(r, (c.bit+32-s2+s1)%32)
+		s.q[i+4] = s.step2(i)
+	}
+}
+
+// round2Fixable lists, per corrected round-2 step, the condition bits
+// (from 0) whose modification does not disturb a round-1 condition.
+var round2Fixable = map[int]map[uint]bool{
+	16: {18: true, 25: true, 26: true, 28: true, 31: true},
+	17: {18: true, 25: true, 26: true, 28: true, 31: true},
+	18: {29: true},
+}
+
+// MD4Collision is a pair of distinct one-block messages with the same MD4
+// hash, and how many candidate blocks were tried to find it.
+type MD4Collision struct {
+	M1, M2   []byte
+	Attempts int
+}
+
+// wangDelta applies the Wang et al. message difference:
+// m1 += 2^31, m2 += 2^31 - 2^28, m12 -= 2^16.
+func wangDelta(m [16]uint32) [16]uint32 {
+	m[1] += 1 << 31
+	m[2] += 1<<31 - 1<<28
+	m[12] -= 1 << 16
+	return m
+}
+
+func md4Words(m [16]uint32) []byte {
+	out := make([]byte, md4.BlockSize)
+	for i, w := range m {
+		binary.LittleEndian.PutUint32(out[4*i:], w)
+	}
+	return out
+}
+
+// FindMD4Collision implements Challenge 55, Wang et al.'s MD4 collision.
+// Each attempt draws a random block, forces every round-1 sufficient
+// condition by single-step message modification, then corrects the a5, d5
+// and c5 conditions that multi-step modification can reach without
+// breaking round 1. The remaining conditions hold by chance, so the block
+// and its difference-applied twin are compressed with md4.Block until they
+// collide, or maxAttempts runs out.
+func FindMD4Collision(maxAttempts int) (*MD4Collision, error) {
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var s md4State
+		copy(s.q[:4], md4IV[:])
+		raw := cu.RandomBytes(md4.BlockSize)
+		for i := range s.m {
+			s.m[i] = binary.LittleEndian.Uint32(raw[4*i:])
+		}
+
+		for i := 0; i < 16; i++ {
+			s.q[i+4] = s.step1(i)
+			s.force(i)
+			s.solve1(i)
+		}
+		for i := 16; i < 19; i++ {
+			s.fixRound2(i, round2Fixable[i])
+		}
+
+		m1, m2 := md4Words(s.m), md4Words(wangDelta(s.m))
+		h1, h2 := md4IVState(), md4IVState()
+		md4.Block(&h1, m1)
+		md4.Block(&h2, m2)
+		if h1 == h2 && !bytes.Equal(m1, m2) {
+			return &MD4Collision{M1: m1, M2: m2, Attempts: attempt}, nil
+		}
+	}
+	return nil, errors.ErrCollisionNotFound
+}
+
+// md4IVState returns the MD4 IV in md4.Block's a, b, c, d order.
+func md4IVState() [4]uint32 {
+	return [4]uint32{md4IV[0], md4IV[3], md4IV[2], md4IV[1]}
+}
@@ -321,6 +321,19 @@ func Unpad(b []byte, k int) ([]byte, error) {
 	return b[:len(b)-int(b[len(b)-1])], nil
 }
 
+// ZeroPad pads data with zero bytes up to a multiple of k bytes.
+// Unlike PKCS#7 it adds nothing to data that is already aligned (except an
+// empty input, which becomes one zero block), so it cannot be undone.
+func ZeroPad(b []byte, k int) []byte {
+	n := len(b) + (k-len(b)%k)%k
+	if n == 0 {
+		n = k
+	}
+	out := make([]byte, n)
+	copy(out, b)
+	return out
+}
+
 func (c CryptoBytes) SSLECBEncrypt(key []byte, pad bool) ([]byte, error) {
 	if len(key) != 16 {
 		return nil, errors.ErrBadKeySize
@@ -429,6 +442,24 @@ func (c CryptoBytes) SSLCBCDecrypt(key, iv []byte, pad bool) ([]byte, error) {
 	return out, nil
 }
 
+// CBCMAC returns the last block of the AES-CBC encryption of the
+// zero-padded data, which authenticates the whole message.
+func (c CryptoBytes) CBCMAC(key, iv []byte) ([]byte, error) {
+	out, err := CryptoBytes(ZeroPad(c, aes.BlockSize)).SSLCBCEncrypt(key, iv, false)
+	if err != nil {
+		return nil, err
+	}
+	return out[len(out)-aes.BlockSize:], nil
+}
+
+// CBCMACHash is the CBC-MAC of Challenge 50 used as a hash function: the
+// key YELLOW SUBMARINE and a zero IV are public, so anyone can compute it.
+// As in the challenge the message is PKCS#7 padded rather than zero padded.
+func CBCMACHash(msg []byte) []byte {
+	out, _ := CryptoBytes(msg).SSLCBCEncrypt([]byte("YELLOW SUBMARINE"), make([]byte, aes.BlockSize), true)
+	return out[len(out)-aes.BlockSize:]
+}
+
 // SSLCTREncrypt encrypts data using AES in CTR (Counter) mode.
 // CTR mode turns a block cipher into a stream cipher by encrypting a counter
 // and XORing the result with the plaintext. It's symmetric: encryption = decryption.
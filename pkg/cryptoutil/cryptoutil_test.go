@@ -1,6 +1,7 @@
 package cryptoutil
 
 import (
+	"encoding/hex"
 	"testing"
 )
 
@@ -279,6 +280,66 @@ func TestUnpad(t *testing.T) {
 	}
 }
 
+func TestZeroPad(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []byte
+	}{
+		{
+			name:  "short block",
+			input: []byte("hello"),
+			want:  []byte("hello\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"),
+		},
+		{
+			name:  "aligned",
+			input: []byte("YELLOW SUBMARINE"),
+			want:  []byte("YELLOW SUBMARINE"),
+		},
+		{
+			name:  "empty",
+			input: nil,
+			want:  make([]byte, 16),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ZeroPad(tt.input, 16); string(got) != string(tt.want) {
+				t.Errorf("ZeroPad() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCBCMAC(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, 16)
+	msg := CryptoBytes("alert('MZA who was that?');\n")
+
+	mac, err := msg.CBCMAC(key, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := CryptoBytes(ZeroPad(msg, 16)).SSLCBCEncrypt(key, iv, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(mac) != string(ct[len(ct)-16:]) {
+		t.Errorf("CBCMAC() = %x, want last block %x", mac, ct[len(ct)-16:])
+	}
+	if _, err := msg.CBCMAC(key, iv[:8]); err == nil {
+		t.Error("CBCMAC() should reject a short IV")
+	}
+}
+
+func TestCBCMACHash(t *testing.T) {
+	got := CBCMACHash([]byte("alert('MZA who was that?');\n"))
+	if want := "296b8d7cb78a243dda4d0a61d33bbdd1"; hex.EncodeToString(got) != want {
+		t.Errorf("CBCMACHash() = %x, want %s", got, want)
+	}
+}
+
 func TestRandomBytes(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,152 @@
+package dh
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// nistPrime is the 1536-bit MODP prime from RFC 3526 used in Challenge 33.
+const nistPrime = "ffffffffffffffffc90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74" +
+	"020bbea63b139b22514a08798e3404ddef9519b3cd3a431b302b0a6df25f1437" +
+	"4fe1356d6d51c245e485b576625e7ec6f44c42e9a637ed6b0bff5cb6f406b7ed" +
+	"ee386bfb5a899fa5ae9f24117c4b1fe649286651ece45b3dc2007cb8a163bf05" +
+	"98da48361c55d39a69163fa8fd24cf5f83655d23dca3ad961c62f356208552bb" +
+	"9ed529077096966d670c354e4abc9804f1746c08ca237327ffffffffffffffff"
+
+// Challenge 57 and 58 parameters: g generates a subgroup of prime order q,
+// and (p-1)/q is full of small factors.
+const (
+	p57 = "7199773997391911030609999317773941274322764333428698921736339643928346453700085358802973900485592910475480089726140708102474957429903531369589969318716771"
+	g57 = "4565356397095740655436854503483826832136106141639563487732438195343690437606117828318042418238184896212352329118608100083187535033402010599512641674644143"
+	q57 = "236234353446506858198510045061214171961"
+
+	p58 = "11470374874925275658116663507232161402086650258453896274534991676898999262641581519101074740642369848233294239851519212341844337347119899874391456329785623"
+	g58 = "622952335333961296978159266084741085889881358738459939978290179936063635566740258555167783009058567397963466103140082647486611657350811560630587013183357"
+	q58 = "335062023296420808191071248367701059461"
+)
+
+// Group holds the public Diffie-Hellman parameters: a prime modulus P and
+// generator G. Q is the order of G when it is known, nil otherwise.
+type Group struct {
+	P *big.Int
+	G *big.Int
+	Q *big.Int
+}
+
+// KeyPair is a Diffie-Hellman key pair within a Group.
+type KeyPair struct {
+	Group   *Group
+	Private *big.Int
+	Public  *big.Int
+}
+
+// NewGroup returns a group with modulus p and generator g.
+func NewGroup(p, g *big.Int) *Group {
+	return &Group{P: new(big.Int).Set(p), G: new(big.Int).Set(g)}
+}
+
+// NISTGroup returns the 1536-bit MODP group with generator 2.
+func NISTGroup() *Group {
+	p, _ := new(big.Int).SetString(nistPrime, 16)
+	return &Group{P: p, G: big.NewInt(2)}
+}
+
+// Challenge57Group returns the group of Challenge 57, where G has a 128-bit
+// prime order Q.
+func Challenge57Group() *Group {
+	return parseGroup(p57, g57, q57)
+}
+
+// Challenge58Group returns the group of Challenge 58. Its (P-1)/Q has fewer
+// small factors, not enough to pin down a key on their own.
+func Challenge58Group() *Group {
+	return parseGroup(p58, g58, q58)
+}
+
+func parseGroup(p, g, q string) *Group {
+	P, _ := new(big.Int).SetString(p, 10)
+	G, _ := new(big.Int).SetString(g, 10)
+	Q, _ := new(big.Int).SetString(q, 10)
+	return &Group{P: P, G: G, Q: Q}
+}
+
+// ModExp computes base^exp mod m by right-to-left square-and-multiply.
+// Each bit of the exponent squares the running base; set bits also multiply
+// it into the result, so the cost is linear in the exponent's bit length.
+func ModExp(base, exp, m *big.Int) *big.Int {
+	result := big.NewInt(1)
+	if m.Cmp(result) == 0 {
+		return big.NewInt(0)
+	}
+	b := new(big.Int).Mod(base, m)
+	for i := 0; i < exp.BitLen(); i++ {
+		if exp.Bit(i) == 1 {
+			result.Mul(result, b).Mod(result, m)
+		}
+		b.Mul(b, b).Mod(b, m)
+	}
+	return result
+}
+
+// Mul returns x*y mod P, the group operation.
+func (g *Group) Mul(x, y *big.Int) *big.Int {
+	z := new(big.Int).Mul(x, y)
+	return z.Mod(z, g.P)
+}
+
+// Exp returns x^e mod P.
+func (g *Group) Exp(x, e *big.Int) *big.Int {
+	return ModExp(x, e, g.P)
+}
+
+// GenerateKey picks a random private key in [1, P-1), or in [1, Q) when the
+// order Q of G is known, and derives the public key G^a mod P.
+func (g *Group) GenerateKey() (*KeyPair, error) {
+	max := new(big.Int).Sub(g.P, big.NewInt(2))
+	if g.Q != nil {
+		max.Sub(g.Q, big.NewInt(1))
+	}
+	priv, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return nil, err
+	}
+	priv.Add(priv, big.NewInt(1))
+	return &KeyPair{Group: g, Private: priv, Public: ModExp(g.G, priv, g.P)}, nil
+}
+
+// ElementOfOrder returns a random element of order r, a prime dividing P-1:
+// a random h^((P-1)/r) mod P, redrawn while it is 1.
+func (g *Group) ElementOfOrder(r *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	pm1 := new(big.Int).Sub(g.P, one)
+	cofactor, rem := new(big.Int).QuoRem(pm1, r, new(big.Int))
+	if r.Cmp(one) <= 0 || rem.Sign() != 0 {
+		return nil, errors.ErrInvalidParameters
+	}
+	for {
+		h, err := rand.Int(rand.Reader, pm1)
+		if err != nil {
+			return nil, err
+		}
+		h.Add(h, one)
+		if e := ModExp(h, cofactor, g.P); e.Cmp(one) != 0 {
+			return e, nil
+		}
+	}
+}
+
+// SharedSecret combines our private key with the peer's public key: peer^a mod P.
+func (k *KeyPair) SharedSecret(peer *big.Int) *big.Int {
+	return ModExp(peer, k.Private, k.Group.P)
+}
+
+// SessionKey derives a 16-byte AES-128 key from a shared secret by taking
+// the first half of SHA-256 over its big-endian bytes. The result can be
+// passed straight to SSLCBCEncrypt/SSLCBCDecrypt.
+func SessionKey(secret *big.Int) []byte {
+	sum := sha256.Sum256(secret.Bytes())
+	return sum[:16]
+}
@@ -0,0 +1,166 @@
+package dh
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestModExp(t *testing.T) {
+	tests := []struct {
+		name           string
+		base, exp, mod int64
+	}{
+		{name: "small", base: 5, exp: 3, mod: 37},
+		{name: "zero exponent", base: 7, exp: 0, mod: 11},
+		{name: "base larger than modulus", base: 1000, exp: 17, mod: 97},
+		{name: "modulus one", base: 4, exp: 9, mod: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, e, m := big.NewInt(tt.base), big.NewInt(tt.exp), big.NewInt(tt.mod)
+			got := ModExp(b, e, m)
+			want := new(big.Int).Exp(b, e, m)
+			if got.Cmp(want) != 0 {
+				t.Errorf("ModExp() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	g := NISTGroup()
+	e, _ := new(big.Int).SetString("123456789abcdef0123456789abcdef", 16)
+	if got, want := ModExp(g.G, e, g.P), new(big.Int).Exp(g.G, e, g.P); got.Cmp(want) != 0 {
+		t.Errorf("ModExp() over NIST group mismatch")
+	}
+}
+
+func TestNISTGroup(t *testing.T) {
+	g := NISTGroup()
+	if g.P.BitLen() != 1536 {
+		t.Errorf("P bit length = %d, want 1536", g.P.BitLen())
+	}
+	if !g.P.ProbablyPrime(20) {
+		t.Error("P should be prime")
+	}
+	if g.G.Int64() != 2 {
+		t.Errorf("G = %v, want 2", g.G)
+	}
+}
+
+func TestChallengeGroups(t *testing.T) {
+	one := big.NewInt(1)
+	for name, g := range map[string]*Group{"57": Challenge57Group(), "58": Challenge58Group()} {
+		pm1 := new(big.Int).Sub(g.P, one)
+		if new(big.Int).Mod(pm1, g.Q).Sign() != 0 {
+			t.Errorf("group %s: Q does not divide P-1", name)
+		}
+		if ModExp(g.G, g.Q, g.P).Cmp(one) != 0 {
+			t.Errorf("group %s: G^Q != 1", name)
+		}
+		k, err := g.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.Private.Cmp(g.Q) >= 0 {
+			t.Errorf("group %s: private key not below Q", name)
+		}
+	}
+}
+
+func TestGroupMulExp(t *testing.T) {
+	g := NewGroup(big.NewInt(23), big.NewInt(5))
+	if got := g.Mul(big.NewInt(7), big.NewInt(10)); got.Int64() != 1 {
+		t.Errorf("Mul(7, 10) = %v, want 1", got)
+	}
+	// 5^3 * 5^4 = 5^7 mod 23
+	a, b := g.Exp(g.G, big.NewInt(3)), g.Exp(g.G, big.NewInt(4))
+	if got, want := g.Mul(a, b), g.Exp(g.G, big.NewInt(7)); got.Cmp(want) != 0 {
+		t.Errorf("Mul(g^3, g^4) = %v, want %v", got, want)
+	}
+}
+
+func TestElementOfOrder(t *testing.T) {
+	g := Challenge57Group()
+	one := big.NewInt(1)
+	// 7963 is one of the small factors of (p-1)/q
+	r := big.NewInt(7963)
+	h, err := g.ElementOfOrder(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Cmp(one) == 0 || ModExp(h, r, g.P).Cmp(one) != 0 {
+		t.Errorf("ElementOfOrder(%v) = %v does not have order %v", r, h, r)
+	}
+	if _, err := g.ElementOfOrder(big.NewInt(7)); err == nil {
+		t.Error("ElementOfOrder() should reject an r not dividing P-1")
+	}
+}
+
+func TestSharedSecret(t *testing.T) {
+	g := NISTGroup()
+	a, err := g.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := g.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Private.Sign() <= 0 || a.Private.Cmp(g.P) >= 0 {
+		t.Errorf("private key out of range: %v", a.Private)
+	}
+	s1, s2 := a.SharedSecret(b.Public), b.SharedSecret(a.Public)
+	if s1.Cmp(s2) != 0 {
+		t.Error("shared secrets differ")
+	}
+	if len(SessionKey(s1)) != 16 {
+		t.Errorf("SessionKey() length = %d, want 16", len(SessionKey(s1)))
+	}
+}
+
+func TestEchoProtocol(t *testing.T) {
+	a, b := Pipe()
+	errc := make(chan error, 1)
+	go func() { errc <- Bob(b) }()
+
+	msg := []byte("hello bob")
+	echo, err := Alice(a, NISTGroup(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if string(echo) != string(msg) {
+		t.Errorf("echo = %q, want %q", echo, msg)
+	}
+}
+
+func TestConnPeerClosed(t *testing.T) {
+	a, b := Pipe()
+	b.Close()
+	if err := a.Send(Message{}); err == nil {
+		t.Error("Send() should fail once the peer closed")
+	}
+	if _, err := a.Recv(); err == nil {
+		t.Error("Recv() should fail once the peer closed")
+	}
+}
+
+func TestSealOpenMessage(t *testing.T) {
+	key := SessionKey(big.NewInt(42))
+	sealed, err := SealMessage(key, []byte("attack at dawn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := OpenMessage(key, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "attack at dawn" {
+		t.Errorf("OpenMessage() = %q", got)
+	}
+	if _, err := OpenMessage(key, sealed[:10]); err == nil {
+		t.Error("OpenMessage() should reject short input")
+	}
+}
@@ -0,0 +1,177 @@
+package dh
+
+import (
+	"math/big"
+
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// Message is one step of the echo protocol from Challenges 34-35.
+// Each step fills in only the fields it needs:
+//
+//	A -> B: P, G        (group proposal)
+//	B -> A: P, G        (acknowledged group, which A adopts)
+//	A -> B: Public      (A = g^a)
+//	B -> A: Public      (B = g^b)
+//	A -> B: Ciphertext  (AES-CBC(key, iv, msg) || iv)
+//	B -> A: Ciphertext  (B's re-encryption of the same msg)
+type Message struct {
+	P, G       *big.Int
+	Public     *big.Int
+	Ciphertext []byte
+}
+
+// Conn is one end of an in-memory, message-oriented connection.
+// Channels are unbuffered, so a completed Send means the peer has the message.
+type Conn struct {
+	send       chan<- Message
+	recv       <-chan Message
+	closed     chan struct{}
+	peerClosed <-chan struct{}
+}
+
+// Pipe returns two connected ends; whatever one sends the other receives.
+func Pipe() (*Conn, *Conn) {
+	ab, ba := make(chan Message), make(chan Message)
+	aClosed, bClosed := make(chan struct{}), make(chan struct{})
+	a := &Conn{send: ab, recv: ba, closed: aClosed, peerClosed: bClosed}
+	b := &Conn{send: ba, recv: ab, closed: bClosed, peerClosed: aClosed}
+	return a, b
+}
+
+// Send delivers m to the other end. It fails if the peer has gone away.
+func (c *Conn) Send(m Message) error {
+	select {
+	case c.send <- m:
+		return nil
+	case <-c.peerClosed:
+		return errors.ErrUnexpectedMessage
+	}
+}
+
+// Recv waits for the next message. It fails once the peer has closed.
+func (c *Conn) Recv() (Message, error) {
+	select {
+	case m := <-c.recv:
+		return m, nil
+	case <-c.peerClosed:
+		return Message{}, errors.ErrUnexpectedMessage
+	}
+}
+
+// Close tells the peer no more messages will be sent. Call it once.
+func (c *Conn) Close() { close(c.closed) }
+
+// SealMessage encrypts msg under key with a fresh IV and appends the IV.
+func SealMessage(key, msg []byte) ([]byte, error) {
+	iv := cu.RandomBytes(16)
+	ct, err := cu.CryptoBytes(msg).SSLCBCEncrypt(key, iv, true)
+	if err != nil {
+		return nil, err
+	}
+	return append(ct, iv...), nil
+}
+
+// OpenMessage reverses SealMessage.
+func OpenMessage(key, sealed []byte) ([]byte, error) {
+	if len(sealed) < 32 {
+		return nil, errors.ErrUnexpectedMessage
+	}
+	ct, iv := sealed[:len(sealed)-16], sealed[len(sealed)-16:]
+	return cu.CryptoBytes(ct).SSLCBCDecrypt(key, iv, true)
+}
+
+// Alice runs the initiator side of the echo protocol: she proposes group,
+// adopts whatever group B acknowledges, sends msg encrypted under the
+// session key and returns B's decrypted echo.
+func Alice(conn *Conn, group *Group, msg []byte) ([]byte, error) {
+	defer conn.Close()
+	if err := conn.Send(Message{P: group.P, G: group.G}); err != nil {
+		return nil, err
+	}
+	ack, err := conn.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if ack.P == nil || ack.G == nil {
+		return nil, errors.ErrUnexpectedMessage
+	}
+	kp, err := NewGroup(ack.P, ack.G).GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Send(Message{Public: kp.Public}); err != nil {
+		return nil, err
+	}
+	peer, err := conn.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if peer.Public == nil {
+		return nil, errors.ErrUnexpectedMessage
+	}
+	key := SessionKey(kp.SharedSecret(peer.Public))
+
+	sealed, err := SealMessage(key, msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Send(Message{Ciphertext: sealed}); err != nil {
+		return nil, err
+	}
+	echo, err := conn.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return OpenMessage(key, echo.Ciphertext)
+}
+
+// Bob runs the responder side: he accepts the proposed group, completes the
+// key exchange and echoes A's message back under a fresh IV.
+func Bob(conn *Conn) error {
+	defer conn.Close()
+	proposal, err := conn.Recv()
+	if err != nil {
+		return err
+	}
+	if proposal.P == nil || proposal.G == nil {
+		return errors.ErrUnexpectedMessage
+	}
+	group := NewGroup(proposal.P, proposal.G)
+	if err := conn.Send(Message{P: group.P, G: group.G}); err != nil {
+		return err
+	}
+	peer, err := conn.Recv()
+	if err != nil {
+		return err
+	}
+	if peer.Public == nil {
+		return errors.ErrUnexpectedMessage
+	}
+	kp, err := group.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(Message{Public: kp.Public}); err != nil {
+		return err
+	}
+	key := SessionKey(kp.SharedSecret(peer.Public))
+
+	in, err := conn.Recv()
+	if err != nil {
+		return err
+	}
+	msg, err := OpenMessage(key, in.Ciphertext)
+	if err != nil {
+		return err
+	}
+	sealed, err := SealMessage(key, msg)
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(Message{Ciphertext: sealed}); err != nil {
+		return err
+	}
+	return nil
+}
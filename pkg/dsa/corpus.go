@@ -0,0 +1,79 @@
+package dsa
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// SignedMessage is one entry of a Challenge 44 style signature corpus.
+type SignedMessage struct {
+	Msg    string
+	Sig    Signature
+	Digest []byte
+}
+
+// ParseSignedMessages reads entries in the Challenge 44 format, four lines each:
+//
+//	msg: <message>
+//	s: <decimal s>
+//	r: <decimal r>
+//	m: <hex SHA-1 of message>
+//
+// Blank lines between entries are ignored. The message keeps any trailing
+// whitespace after "msg: " since it is part of what was hashed.
+func ParseSignedMessages(r io.Reader) ([]SignedMessage, error) {
+	var out []SignedMessage
+	var cur SignedMessage
+	field := 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if field != 0 {
+			line = strings.TrimSpace(line)
+		}
+		if field == 0 && strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, errors.ErrInvalidSignatureCorpus
+		}
+		switch {
+		case field == 0 && key == "msg":
+			cur = SignedMessage{Msg: value}
+		case field == 1 && key == "s":
+			cur.Sig.S, ok = new(big.Int).SetString(value, 10)
+		case field == 2 && key == "r":
+			cur.Sig.R, ok = new(big.Int).SetString(value, 10)
+		case field == 3 && key == "m":
+			// Hashes are printed without leading zeros
+			if len(value)%2 == 1 {
+				value = "0" + value
+			}
+			var err error
+			cur.Digest, err = hex.DecodeString(value)
+			ok = err == nil
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, errors.ErrInvalidSignatureCorpus
+		}
+		field = (field + 1) % 4
+		if field == 0 {
+			out = append(out, cur)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if field != 0 {
+		return nil, errors.ErrInvalidSignatureCorpus
+	}
+	return out, nil
+}
@@ -0,0 +1,181 @@
+package dsa
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+// Parameters are the DSA domain parameters: primes P and Q with Q | P-1,
+// and a generator G of the order-Q subgroup.
+type Parameters struct {
+	P, Q, G *big.Int
+}
+
+// PublicKey is a DSA public key y = g^x mod p.
+type PublicKey struct {
+	Parameters
+	Y *big.Int
+}
+
+// PrivateKey is a DSA key pair.
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+// Signature is a DSA signature (r, s).
+type Signature struct {
+	R, S *big.Int
+}
+
+// NonceSource returns the per-signature secret k in [1, q).
+// Signing takes it as a parameter so tests and attacks can control it.
+type NonceSource func(q *big.Int) (*big.Int, error)
+
+// ChallengeParameters returns the 1024/160-bit parameters from Challenge 43.
+func ChallengeParameters() Parameters {
+	p, _ := new(big.Int).SetString("800000000000000089e1855218a0e7dac38136ffafa72eda7"+
+		"859f2171e25e65eac698c1702578b07dc2a1076da241c76c6"+
+		"2d374d8389ea5aeffd3226a0530cc565f3bf6b50929139ebe"+
+		"ac04f48c3c84afb796d61e5a4f9a8fda812ab59494232c7d2"+
+		"b4deb50aa18ee9e132bfa85ac4374d7f9091abc3d015efc87"+
+		"1a584471bb1", 16)
+	q, _ := new(big.Int).SetString("f4f47f05794b256174bba6e9b396a7707e563c5b", 16)
+	g, _ := new(big.Int).SetString("5958c9d3898b224b12672c0b98e06c60df923cb8bc999d11"+
+		"9458fef538b8fa4046c8db53039db620c094c9fa077ef389b5"+
+		"322a559946a71903f990f1f7e0e025e2d7f7cf494aff1a047"+
+		"0f5b64c36b625a097f1651fe775323556fe00b3608c887892"+
+		"878480e99041be601a62166ca6894bdd41a7054ec89f756ba"+
+		"9fc95302291", 16)
+	return Parameters{P: p, Q: q, G: g}
+}
+
+// RandomNonce draws k uniformly from [1, q).
+func RandomNonce(q *big.Int) (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(q, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// GenerateKey picks x in [1, q) and computes y = g^x mod p.
+func GenerateKey(params Parameters) (*PrivateKey, error) {
+	x, err := RandomNonce(params.Q)
+	if err != nil {
+		return nil, err
+	}
+	y := new(big.Int).Exp(params.G, x, params.P)
+	return &PrivateKey{PublicKey: PublicKey{Parameters: params, Y: y}, X: x}, nil
+}
+
+// VerifyOption relaxes a check in Verify, mimicking a lax implementation.
+type VerifyOption func(*verifyConfig)
+
+type verifyConfig struct {
+	skipRange  bool
+	skipParams bool
+}
+
+// SkipRangeChecks accepts r and s outside 0 < r, s < q (Challenge 45, g=0).
+func SkipRangeChecks() VerifyOption {
+	return func(c *verifyConfig) { c.skipRange = true }
+}
+
+// SkipParameterChecks trusts the domain parameters as given, even a
+// generator outside 1 < g < p (Challenge 45, g=p+1).
+func SkipParameterChecks() VerifyOption {
+	return func(c *verifyConfig) { c.skipParams = true }
+}
+
+// Valid reports whether the generator lies strictly between 1 and p.
+// Anything else (0, 1, p, p+1...) makes every signature trivial.
+func (params Parameters) Valid() bool {
+	return params.G.Cmp(big.NewInt(1)) > 0 && params.G.Cmp(params.P) < 0
+}
+
+// Sign signs digest (read as the integer H(m)) using a nonce from nonce:
+// r = (g^k mod p) mod q, s = k^-1 (H(m) + x*r) mod q.
+// A zero r or s means a bad nonce and another one is drawn, up to
+// maxNonceAttempts times: a broken source returning the same bad k would
+// otherwise loop forever.
+func (k *PrivateKey) Sign(digest []byte, nonce NonceSource) (*Signature, error) {
+	// A degenerate g would make r zero forever
+	if !k.Parameters.Valid() {
+		return nil, errors.ErrInvalidParameters
+	}
+	h := new(big.Int).SetBytes(digest)
+	for attempt := 0; attempt < maxNonceAttempts; attempt++ {
+		kk, err := nonce(k.Q)
+		if err != nil {
+			return nil, err
+		}
+		r := new(big.Int).Exp(k.G, kk, k.P)
+		r.Mod(r, k.Q)
+		if r.Sign() == 0 {
+			continue
+		}
+		kInv, err := rsa.InvMod(kk, k.Q)
+		if err != nil {
+			continue
+		}
+		s := new(big.Int).Mul(k.X, r)
+		s.Add(s, h).Mul(s, kInv).Mod(s, k.Q)
+		if s.Sign() == 0 {
+			continue
+		}
+		return &Signature{R: r, S: s}, nil
+	}
+	return nil, errors.ErrBadNonce
+}
+
+// maxNonceAttempts bounds the nonces Sign draws. With a sound source a bad
+// k has probability about 2/q, so running out means the source is broken.
+const maxNonceAttempts = 64
+
+// Verify checks sig over digest: with w = s^-1, u1 = H(m)w and u2 = rw,
+// the signature is valid when (g^u1 * y^u2 mod p) mod q equals r.
+// By default it also checks the generator and 0 < r, s < q; opts turn
+// those checks off.
+func (k *PublicKey) Verify(digest []byte, sig *Signature, opts ...VerifyOption) error {
+	var cfg verifyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !cfg.skipParams && !k.Parameters.Valid() {
+		return errors.ErrInvalidParameters
+	}
+	if !cfg.skipRange && (sig.R.Sign() <= 0 || sig.R.Cmp(k.Q) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(k.Q) >= 0) {
+		return errors.ErrVerificationFailed
+	}
+	w, err := rsa.InvMod(sig.S, k.Q)
+	if err != nil {
+		return errors.ErrVerificationFailed
+	}
+	u1 := new(big.Int).SetBytes(digest)
+	u1.Mul(u1, w).Mod(u1, k.Q)
+	u2 := new(big.Int).Mul(sig.R, w)
+	u2.Mod(u2, k.Q)
+
+	v := new(big.Int).Exp(k.G, u1, k.P)
+	v.Mul(v, new(big.Int).Exp(k.Y, u2, k.P)).Mod(v, k.P).Mod(v, k.Q)
+	if v.Cmp(sig.R) != 0 {
+		return errors.ErrVerificationFailed
+	}
+	return nil
+}
+
+// PrivateKeyFromNonce recovers x from a signature whose nonce k is known:
+// x = (s*k - H(m)) * r^-1 mod q.
+func PrivateKeyFromNonce(params Parameters, digest []byte, sig *Signature, k *big.Int) (*big.Int, error) {
+	rInv, err := rsa.InvMod(sig.R, params.Q)
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Int).Mul(sig.S, k)
+	x.Sub(x, new(big.Int).SetBytes(digest)).Mul(x, rInv).Mod(x, params.Q)
+	return x, nil
+}
@@ -0,0 +1,133 @@
+package dsa
+
+import (
+	"crypto/sha1"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+func TestChallengeParameters(t *testing.T) {
+	params := ChallengeParameters()
+	if !params.P.ProbablyPrime(20) || !params.Q.ProbablyPrime(20) {
+		t.Fatal("p and q should be prime")
+	}
+	pm1 := new(big.Int).Sub(params.P, big.NewInt(1))
+	if new(big.Int).Mod(pm1, params.Q).Sign() != 0 {
+		t.Error("q should divide p-1")
+	}
+	if new(big.Int).Exp(params.G, params.Q, params.P).Cmp(big.NewInt(1)) != 0 {
+		t.Error("g should have order q")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	k, err := GenerateKey(ChallengeParameters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha1.Sum([]byte("sign me"))
+	sig, err := k.Sign(digest[:], RandomNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Verify(digest[:], sig); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	other := sha1.Sum([]byte("not what was signed"))
+	if err := k.Verify(other[:], sig); err == nil {
+		t.Error("Verify() accepted a signature over another message")
+	}
+	bad := &Signature{R: sig.R, S: new(big.Int).Add(sig.S, big.NewInt(1))}
+	if err := k.Verify(digest[:], bad); err == nil {
+		t.Error("Verify() accepted a tampered signature")
+	}
+	if err := k.Verify(digest[:], &Signature{R: big.NewInt(0), S: sig.S}); err == nil {
+		t.Error("Verify() accepted r = 0")
+	}
+}
+
+func TestSignBrokenNonceSource(t *testing.T) {
+	k, err := GenerateKey(ChallengeParameters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// k = 0 has no inverse, so every attempt is rejected
+	zero := func(*big.Int) (*big.Int, error) { return big.NewInt(0), nil }
+	digest := sha1.Sum([]byte("sign me"))
+	if _, err := k.Sign(digest[:], zero); err != errors.ErrBadNonce {
+		t.Errorf("Sign() error = %v, want ErrBadNonce", err)
+	}
+}
+
+func TestPrivateKeyFromNonce(t *testing.T) {
+	k, err := GenerateKey(ChallengeParameters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed := big.NewInt(12345)
+	digest := sha1.Sum([]byte("leaky nonce"))
+	sig, err := k.Sign(digest[:], func(*big.Int) (*big.Int, error) { return fixed, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := PrivateKeyFromNonce(k.Parameters, digest[:], sig, fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Cmp(k.X) != 0 {
+		t.Errorf("PrivateKeyFromNonce() = %v, want %v", x, k.X)
+	}
+}
+
+func TestParseSignedMessages(t *testing.T) {
+	corpus := "msg: Listen for me, you better listen for me now. \n" +
+		"s: 1267396447369736888040262262183731677867615804316\n" +
+		"r: 1105520928110492191417703162650245113664610474875\n" +
+		"m: a4db3de27e2db3e5ef085ced2bced91b82e0df19\n" +
+		"msg: Pure black people mon is all I mon know. \n" +
+		"s: 1021643638653719618255840562522049391608552714967\n" +
+		"r: 1105520928110492191417703162650245113664610474875\n" +
+		"m: d22804c4899b522b23eda34d2137cd8cc22b9ce8\n"
+
+	msgs, err := ParseSignedMessages(strings.NewReader(corpus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("parsed %d entries, want 2", len(msgs))
+	}
+	if msgs[0].Msg != "Listen for me, you better listen for me now. " {
+		t.Errorf("Msg = %q", msgs[0].Msg)
+	}
+	if msgs[1].Sig.R.String() != "1105520928110492191417703162650245113664610474875" {
+		t.Errorf("R = %v", msgs[1].Sig.R)
+	}
+	digest := sha1.Sum([]byte(msgs[0].Msg))
+	if string(digest[:]) != string(msgs[0].Digest) {
+		t.Errorf("Digest = %x, want %x", msgs[0].Digest, digest)
+	}
+}
+
+func TestParseSignedMessagesInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		corpus string
+	}{
+		{name: "truncated entry", corpus: "msg: hi\ns: 1\n"},
+		{name: "fields out of order", corpus: "msg: hi\nr: 1\ns: 1\nm: 00\n"},
+		{name: "bad number", corpus: "msg: hi\ns: xyz\nr: 1\nm: 00\n"},
+		{name: "bad hash", corpus: "msg: hi\ns: 1\nr: 1\nm: zz\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSignedMessages(strings.NewReader(tt.corpus)); err == nil {
+				t.Error("ParseSignedMessages() should fail")
+			}
+		})
+	}
+}
@@ -20,4 +20,36 @@ var (
 	ErrECBEncryptionFailed = errors.New("ecb encryption failed")
 	ErrCBCEncryptionFailed = errors.New("cbc encryption failed")
 	ErrFailedAesCtrEncrypt = errors.New("failed aes ctr encrypt")
+
+	ErrLengthExtensionFailed = errors.New("length extension failed")
+	ErrTimingAttackFailed    = errors.New("timing attack failed")
+
+	ErrUnexpectedMessage = errors.New("unexpected protocol message")
+	ErrMITMFailed        = errors.New("mitm failed")
+
+	ErrUnknownUser      = errors.New("unknown user")
+	ErrSRPLoginFailed   = errors.New("srp login failed")
+	ErrPasswordNotFound = errors.New("password not found")
+
+	ErrNoInverse              = errors.New("no modular inverse")
+	ErrMessageTooLong         = errors.New("message too long for rsa key")
+	ErrNotPerfectPower        = errors.New("not a perfect power")
+	ErrReplayedCiphertext     = errors.New("ciphertext already decrypted")
+	ErrUnsupportedHash        = errors.New("unsupported hash")
+	ErrVerificationFailed     = errors.New("signature verification failed")
+	ErrForgeryFailed          = errors.New("signature forgery failed")
+	ErrKeyRecoveryFailed      = errors.New("key recovery failed")
+	ErrInvalidSignatureCorpus = errors.New("invalid signature corpus")
+	ErrInvalidParameters      = errors.New("invalid domain parameters")
+	ErrPaddingOracleFailed    = errors.New("padding oracle attack failed")
+	ErrBadNonce               = errors.New("nonce source gave no usable nonce")
+
+	ErrInvalidMAC     = errors.New("invalid mac")
+	ErrInvalidRequest = errors.New("invalid request")
+
+	ErrCompressionAttackFailed = errors.New("compression attack failed")
+	ErrInvalidStateSize        = errors.New("invalid state size")
+	ErrCollisionNotFound       = errors.New("collision not found")
+	ErrInvalidLength           = errors.New("invalid length")
+	ErrDiscreteLogNotFound     = errors.New("discrete log not found")
 )
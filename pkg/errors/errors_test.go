@@ -80,6 +80,136 @@ func TestErrorVariables(t *testing.T) {
 			err:  ErrFailedAesCtrEncrypt,
 			want: "failed aes ctr encrypt",
 		},
+		{
+			name: "ErrLengthExtensionFailed",
+			err:  ErrLengthExtensionFailed,
+			want: "length extension failed",
+		},
+		{
+			name: "ErrTimingAttackFailed",
+			err:  ErrTimingAttackFailed,
+			want: "timing attack failed",
+		},
+		{
+			name: "ErrUnexpectedMessage",
+			err:  ErrUnexpectedMessage,
+			want: "unexpected protocol message",
+		},
+		{
+			name: "ErrMITMFailed",
+			err:  ErrMITMFailed,
+			want: "mitm failed",
+		},
+		{
+			name: "ErrUnknownUser",
+			err:  ErrUnknownUser,
+			want: "unknown user",
+		},
+		{
+			name: "ErrSRPLoginFailed",
+			err:  ErrSRPLoginFailed,
+			want: "srp login failed",
+		},
+		{
+			name: "ErrPasswordNotFound",
+			err:  ErrPasswordNotFound,
+			want: "password not found",
+		},
+		{
+			name: "ErrNoInverse",
+			err:  ErrNoInverse,
+			want: "no modular inverse",
+		},
+		{
+			name: "ErrMessageTooLong",
+			err:  ErrMessageTooLong,
+			want: "message too long for rsa key",
+		},
+		{
+			name: "ErrNotPerfectPower",
+			err:  ErrNotPerfectPower,
+			want: "not a perfect power",
+		},
+		{
+			name: "ErrReplayedCiphertext",
+			err:  ErrReplayedCiphertext,
+			want: "ciphertext already decrypted",
+		},
+		{
+			name: "ErrUnsupportedHash",
+			err:  ErrUnsupportedHash,
+			want: "unsupported hash",
+		},
+		{
+			name: "ErrVerificationFailed",
+			err:  ErrVerificationFailed,
+			want: "signature verification failed",
+		},
+		{
+			name: "ErrForgeryFailed",
+			err:  ErrForgeryFailed,
+			want: "signature forgery failed",
+		},
+		{
+			name: "ErrKeyRecoveryFailed",
+			err:  ErrKeyRecoveryFailed,
+			want: "key recovery failed",
+		},
+		{
+			name: "ErrInvalidSignatureCorpus",
+			err:  ErrInvalidSignatureCorpus,
+			want: "invalid signature corpus",
+		},
+		{
+			name: "ErrInvalidParameters",
+			err:  ErrInvalidParameters,
+			want: "invalid domain parameters",
+		},
+		{
+			name: "ErrPaddingOracleFailed",
+			err:  ErrPaddingOracleFailed,
+			want: "padding oracle attack failed",
+		},
+		{
+			name: "ErrBadNonce",
+			err:  ErrBadNonce,
+			want: "nonce source gave no usable nonce",
+		},
+		{
+			name: "ErrInvalidMAC",
+			err:  ErrInvalidMAC,
+			want: "invalid mac",
+		},
+		{
+			name: "ErrInvalidRequest",
+			err:  ErrInvalidRequest,
+			want: "invalid request",
+		},
+		{
+			name: "ErrCompressionAttackFailed",
+			err:  ErrCompressionAttackFailed,
+			want: "compression attack failed",
+		},
+		{
+			name: "ErrInvalidStateSize",
+			err:  ErrInvalidStateSize,
+			want: "invalid state size",
+		},
+		{
+			name: "ErrCollisionNotFound",
+			err:  ErrCollisionNotFound,
+			want: "collision not found",
+		},
+		{
+			name: "ErrInvalidLength",
+			err:  ErrInvalidLength,
+			want: "invalid length",
+		},
+		{
+			name: "ErrDiscreteLogNotFound",
+			err:  ErrDiscreteLogNotFound,
+			want: "discrete log not found",
+		},
 	}
 
 	for _, tt := range tests {
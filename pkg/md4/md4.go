@@ -0,0 +1,154 @@
+package md4
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Size is the size of an MD4 checksum in bytes.
+const Size = 16
+
+// BlockSize is the block size of MD4 in bytes.
+const BlockSize = 64
+
+// initState is the standard MD4 initialization vector (RFC 1320, 3.3).
+var initState = [4]uint32{0x67452301, 0xEFCDAB89, 0x98BADCFE, 0x10325476}
+
+// Digest is a pure-Go MD4 implementation (RFC 1320).
+// Its internal state can be injected with NewFromState for length-extension attacks.
+type Digest struct {
+	h   [4]uint32
+	buf [BlockSize]byte
+	nx  int
+	len uint64
+
+	initH   [4]uint32
+	initLen uint64
+}
+
+// New returns an MD4 hash starting from the standard initialization vector.
+func New() *Digest {
+	return NewFromState(initState, 0)
+}
+
+// NewFromState returns an MD4 hash whose registers are set to h and which
+// believes length bytes (original message plus glue padding) were already processed.
+func NewFromState(h [4]uint32, length uint64) *Digest {
+	d := &Digest{initH: h, initLen: length}
+	d.Reset()
+	return d
+}
+
+// Sum returns the MD4 checksum of data.
+func Sum(data []byte) [Size]byte {
+	d := New()
+	d.Write(data)
+	var out [Size]byte
+	copy(out[:], d.Sum(nil))
+	return out
+}
+
+// StateFromSum splits an MD4 checksum back into its four little-endian registers.
+func StateFromSum(sum []byte) [4]uint32 {
+	var h [4]uint32
+	for i := range h {
+		h[i] = binary.LittleEndian.Uint32(sum[4*i:])
+	}
+	return h
+}
+
+// Padding returns the padding MD4 appends to a message of msgLen bytes:
+// 0x80, zeros up to 56 mod 64, then the bit length as a little-endian uint64.
+func Padding(msgLen uint64) []byte {
+	padLen := BlockSize - int((msgLen+8)%BlockSize)
+	if padLen == 0 {
+		padLen = BlockSize
+	}
+	out := make([]byte, padLen+8)
+	out[0] = 0x80
+	binary.LittleEndian.PutUint64(out[padLen:], msgLen<<3)
+	return out
+}
+
+var _ hash.Hash = (*Digest)(nil)
+
+func (d *Digest) Reset() {
+	d.h = d.initH
+	d.nx = 0
+	d.len = d.initLen
+}
+
+func (d *Digest) Size() int      { return Size }
+func (d *Digest) BlockSize() int { return BlockSize }
+
+func (d *Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		c := copy(d.buf[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+		if d.nx == BlockSize {
+			Block(&d.h, d.buf[:])
+			d.nx = 0
+		}
+	}
+	for len(p) >= BlockSize {
+		Block(&d.h, p[:BlockSize])
+		p = p[BlockSize:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.buf[:], p)
+	}
+	return n, nil
+}
+
+// Sum appends the current checksum to b without changing the digest state.
+func (d *Digest) Sum(b []byte) []byte {
+	dup := *d
+	dup.Write(Padding(d.len))
+	out := make([]byte, Size)
+	for i, v := range dup.h {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+	return append(b, out...)
+}
+
+// shifts holds the per-round rotation amounts.
+var shifts = [3][4]int{{3, 7, 11, 19}, {3, 5, 9, 13}, {3, 9, 11, 15}}
+
+// round3Order is the message word order used by round 3.
+var round3Order = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+
+// Block runs the MD4 compression function over one 64-byte block.
+// It is exported so collision searches can compress single blocks directly.
+func Block(h *[4]uint32, p []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(p[4*i:])
+	}
+	a, b, c, d := h[0], h[1], h[2], h[3]
+
+	// Round 1: F(x,y,z) = (x & y) | (^x & z)
+	for i := 0; i < 16; i++ {
+		t := a + ((b & c) | (^b & d)) + x[i]
+		a, b, c, d = d, bits.RotateLeft32(t, shifts[0][i%4]), b, c
+	}
+	// Round 2: G(x,y,z) = majority(x, y, z)
+	for i := 0; i < 16; i++ {
+		k := (i%4)*4 + i/4
+		t := a + ((b & c) | (b & d) | (c & d)) + x[k] + 0x5A827999
+		a, b, c, d = d, bits.RotateLeft32(t, shifts[1][i%4]), b, c
+	}
+	// Round 3: H(x,y,z) = x ^ y ^ z
+	for i := 0; i < 16; i++ {
+		t := a + (b ^ c ^ d) + x[round3Order[i]] + 0x6ED9EBA1
+		a, b, c, d = d, bits.RotateLeft32(t, shifts[2][i%4]), b, c
+	}
+
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+}
@@ -0,0 +1,75 @@
+package md4
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSumRFC1320(t *testing.T) {
+	// Test suite from RFC 1320, appendix A.5
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{"a", "bde52cb31de33e46245e05fbdbd6fb24"},
+		{"abc", "a448017aaf21d8525fc10ae87aa6729d"},
+		{"message digest", "d9130a8164549fe818874806e1c7014b"},
+		{"abcdefghijklmnopqrstuvwxyz", "d79e1c308aa5bbcdeea8ed63df412da9"},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", "043f8582f241db351ce627e153e7f0e4"},
+		{"12345678901234567890123456789012345678901234567890123456789012345678901234567890", "e33b4ddc9c38f2199c3e7b164fcc0536"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := Sum([]byte(tt.input))
+			if hex.EncodeToString(got[:]) != tt.want {
+				t.Errorf("Sum(%q) = %x, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDigestIncrementalWrite(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 30)
+	want := Sum(data)
+	d := New()
+	for i := 0; i < len(data); i += 13 {
+		end := i + 13
+		if end > len(data) {
+			end = len(data)
+		}
+		d.Write(data[i:end])
+	}
+	if got := d.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("Sum() = %x, want %x", got, want)
+	}
+}
+
+func TestPadding(t *testing.T) {
+	p := Padding(3)
+	if len(p) != 61 {
+		t.Fatalf("Padding(3) length = %d, want 61", len(p))
+	}
+	// The bit length is little-endian, unlike SHA-1
+	if p[len(p)-8] != 24 {
+		t.Errorf("Padding(3) length field = %x, want little-endian 24", p[len(p)-8:])
+	}
+}
+
+func TestNewFromState(t *testing.T) {
+	msg := []byte("secret-prefixed message")
+	suffix := []byte(";admin=true")
+	glue := Padding(uint64(len(msg)))
+	full := append(append(append([]byte(nil), msg...), glue...), suffix...)
+
+	sum := Sum(msg)
+	d := NewFromState(StateFromSum(sum[:]), uint64(len(msg)+len(glue)))
+	d.Write(suffix)
+
+	want := Sum(full)
+	if got := d.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("extended Sum() = %x, want %x", got, want)
+	}
+}
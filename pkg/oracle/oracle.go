@@ -5,5 +5,15 @@
 // - oracle13.go: Challenge 13 (ECB cut-and-paste)
 // - oracle14.go: Challenge 14 (Byte-at-a-time with random prefix)
 // - oracle17.go: Challenge 17 (CBC padding oracle)
+// - oracle28.go: Challenges 28-29 (SHA-1 secret-prefix MAC)
+// - oracle30.go: Challenge 30 (MD4 secret-prefix MAC)
+// - oracle31.go: Challenges 31-32 (HMAC-SHA1 timing leak web app)
+// - oracle41.go: Challenge 41 (RSA decryption server with replay cache)
+// - oracle46.go: Challenge 46 (RSA parity oracle)
+// - oracle47.go: Challenges 47-48 (PKCS#1 v1.5 padding oracle)
+// - oracle49.go: Challenge 49 (CBC-MAC money-transfer API, v1 and v2)
+// - oracle51.go: Challenge 51 (compression length leak)
+// - oracle56.go: Challenge 56 (RC4 single-byte biases)
+// - oracle57.go: Challenges 57-58 (DH MAC'ing Bob, no public key validation)
 // - helper.go: Shared utility functions (randomBytes, randomInt)
 package oracle
@@ -0,0 +1,43 @@
+package oracle
+
+import (
+	"bytes"
+	"crypto/subtle"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/sha1x"
+)
+
+// Oracle28 implements Challenges 28-29: a secret-prefix SHA-1 MAC.
+// MAC(msg) = SHA1(key || msg) with a key of unknown length.
+// Attacker can extend a valid message without knowing the key.
+type Oracle28 struct {
+	Key []byte
+}
+
+func NewOracle28() *Oracle28 {
+	return &Oracle28{Key: randomBytes(randomInt(1, 64))}
+}
+
+// MAC authenticates msg by hashing it behind the secret key.
+func (o *Oracle28) MAC(msg []byte) []byte {
+	d := sha1x.New()
+	d.Write(o.Key)
+	d.Write(msg)
+	return d.Sum(nil)
+}
+
+// Verify reports whether mac is a valid MAC for msg.
+func (o *Oracle28) Verify(msg, mac []byte) bool {
+	return subtle.ConstantTimeCompare(o.MAC(msg), mac) == 1
+}
+
+// Message returns the cookie-like message from Challenge 29 and its MAC.
+func (o *Oracle28) Message() ([]byte, []byte) {
+	msg := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	return msg, o.MAC(msg)
+}
+
+// IsAdmin reports whether msg carries a valid MAC and contains ";admin=true".
+func (o *Oracle28) IsAdmin(msg, mac []byte) bool {
+	return o.Verify(msg, mac) && bytes.Contains(msg, []byte(";admin=true"))
+}
@@ -0,0 +1,42 @@
+package oracle
+
+import (
+	"bytes"
+	"crypto/subtle"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/md4"
+)
+
+// Oracle30 implements Challenge 30: a secret-prefix MD4 MAC.
+// Same construction as Oracle28, MAC(msg) = MD4(key || msg).
+type Oracle30 struct {
+	Key []byte
+}
+
+func NewOracle30() *Oracle30 {
+	return &Oracle30{Key: randomBytes(randomInt(1, 64))}
+}
+
+// MAC authenticates msg by hashing it behind the secret key.
+func (o *Oracle30) MAC(msg []byte) []byte {
+	d := md4.New()
+	d.Write(o.Key)
+	d.Write(msg)
+	return d.Sum(nil)
+}
+
+// Verify reports whether mac is a valid MAC for msg.
+func (o *Oracle30) Verify(msg, mac []byte) bool {
+	return subtle.ConstantTimeCompare(o.MAC(msg), mac) == 1
+}
+
+// Message returns the cookie-like message from Challenge 30 and its MAC.
+func (o *Oracle30) Message() ([]byte, []byte) {
+	msg := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	return msg, o.MAC(msg)
+}
+
+// IsAdmin reports whether msg carries a valid MAC and contains ";admin=true".
+func (o *Oracle30) IsAdmin(msg, mac []byte) bool {
+	return o.Verify(msg, mac) && bytes.Contains(msg, []byte(";admin=true"))
+}
@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"time"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/sha1x"
+)
+
+// Oracle31 implements Challenges 31-32: a web app leaking HMAC-SHA1 timing.
+// It serves "?file=...&signature=..." and answers 200 when the hex signature
+// matches HMAC-SHA1(key, file), 500 otherwise. The comparison exits at the
+// first wrong byte and sleeps Delay after every byte, so the response time
+// reveals how many leading bytes of the signature are right.
+type Oracle31 struct {
+	Key   []byte
+	Delay time.Duration
+	// SigLen is the number of leading HMAC bytes the server checks, at
+	// most the full 20; tests shorten it to keep runs fast.
+	SigLen int
+}
+
+// NewOracle31 returns a server checking the first sigLen bytes of the
+// HMAC, 1 <= sigLen <= 20.
+func NewOracle31(delay time.Duration, sigLen int) (*Oracle31, error) {
+	if sigLen < 1 || sigLen > sha1x.Size {
+		return nil, errors.ErrInvalidLength
+	}
+	return &Oracle31{Key: randomBytes(16), Delay: delay, SigLen: sigLen}, nil
+}
+
+// HMAC returns HMAC-SHA1(key, file) built on pkg/sha1x, truncated to SigLen bytes.
+func (o *Oracle31) HMAC(file []byte) []byte {
+	mac := hmac.New(func() hash.Hash { return sha1x.New() }, o.Key)
+	mac.Write(file)
+	return mac.Sum(nil)[:o.SigLen]
+}
+
+// insecureCompare compares a and b byte by byte, bailing out at the first
+// difference and sleeping delay after each byte - the timing leak.
+func insecureCompare(a, b []byte, delay time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+		time.Sleep(delay)
+	}
+	return true
+}
+
+// ServeHTTP validates the file/signature query parameters.
+func (o *Oracle31) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	sig, err := hex.DecodeString(q.Get("signature"))
+	if err != nil {
+		http.Error(w, "bad signature encoding", http.StatusBadRequest)
+		return
+	}
+	if !insecureCompare(o.HMAC([]byte(q.Get("file"))), sig, o.Delay) {
+		http.Error(w, "invalid signature", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
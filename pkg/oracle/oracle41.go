@@ -0,0 +1,55 @@
+package oracle
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"sync"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+// Oracle41 implements Challenge 41: an unpadded RSA decryption server.
+// It decrypts any ciphertext exactly once, remembering the SHA-256 of every
+// ciphertext it has seen, so a captured message cannot simply be replayed.
+type Oracle41 struct {
+	Key *rsa.PrivateKey
+
+	mu   sync.Mutex
+	seen map[[32]byte]bool
+}
+
+func NewOracle41() (*Oracle41, error) {
+	k, err := rsa.GenerateKey(1024, 65537)
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle41{Key: k, seen: map[[32]byte]bool{}}, nil
+}
+
+// PublicKey returns the server's public key.
+func (o *Oracle41) PublicKey() *rsa.PublicKey { return &o.Key.PublicKey }
+
+// Decrypt decrypts ct unless the same ciphertext was submitted before.
+// Only full-size ciphertexts below N are accepted, so each value has a
+// single encoding: a replay cannot slip past the cache by adding or
+// stripping leading zero bytes.
+func (o *Oracle41) Decrypt(ct []byte) ([]byte, error) {
+	k := o.Key
+	if len(ct) != k.Size() {
+		return nil, errors.ErrInvalidLength
+	}
+	c := new(big.Int).SetBytes(ct)
+	if c.Cmp(k.N) >= 0 {
+		return nil, errors.ErrMessageTooLong
+	}
+	h := sha256.Sum256(c.FillBytes(make([]byte, k.Size())))
+	o.mu.Lock()
+	replay := o.seen[h]
+	o.seen[h] = true
+	o.mu.Unlock()
+	if replay {
+		return nil, errors.ErrReplayedCiphertext
+	}
+	return o.Key.DecryptBytes(ct)
+}
@@ -0,0 +1,34 @@
+package oracle
+
+import (
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+// Oracle46 implements Challenge 46: a server that decrypts any RSA
+// ciphertext but only reveals whether the plaintext is even.
+type Oracle46 struct {
+	Key *rsa.PrivateKey
+}
+
+func NewOracle46() (*Oracle46, error) {
+	k, err := rsa.GenerateKey(1024, 65537)
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle46{Key: k}, nil
+}
+
+// PublicKey returns the server's public key.
+func (o *Oracle46) PublicKey() *rsa.PublicKey { return &o.Key.PublicKey }
+
+// IsEven reports whether ct decrypts to an even number.
+func (o *Oracle46) IsEven(ct []byte) (bool, error) {
+	c := new(big.Int).SetBytes(ct)
+	if c.Cmp(o.Key.N) >= 0 {
+		return false, errors.ErrMessageTooLong
+	}
+	return o.Key.Decrypt(c).Bit(0) == 0, nil
+}
@@ -0,0 +1,40 @@
+package oracle
+
+import (
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/rsa"
+)
+
+// Oracle47 implements Challenges 47-48: a server that decrypts RSA
+// ciphertexts and reveals only whether the plaintext starts with 00 02,
+// i.e. whether its PKCS#1 v1.5 padding looks conforming.
+// Queries counts calls to PaddingOK.
+type Oracle47 struct {
+	Key     *rsa.PrivateKey
+	Queries int
+}
+
+// NewOracle47 returns an oracle with a fresh bits-bit key and e=3, as in
+// the challenges (256 bits for 47, 768 for 48).
+func NewOracle47(bits int) (*Oracle47, error) {
+	k, err := rsa.GenerateKey(bits, 3)
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle47{Key: k}, nil
+}
+
+// PublicKey returns the server's public key.
+func (o *Oracle47) PublicKey() *rsa.PublicKey { return &o.Key.PublicKey }
+
+// PaddingOK reports whether ct decrypts to a block beginning 00 02.
+func (o *Oracle47) PaddingOK(ct []byte) bool {
+	o.Queries++
+	c := new(big.Int).SetBytes(ct)
+	if c.Cmp(o.Key.N) >= 0 {
+		return false
+	}
+	em := o.Key.Decrypt(c).FillBytes(make([]byte, o.Key.Size()))
+	return em[0] == 0x00 && em[1] == 0x02
+}
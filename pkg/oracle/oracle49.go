@@ -0,0 +1,140 @@
+package oracle
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// Transfer is one money movement authorised by a request.
+type Transfer struct {
+	From   string
+	To     string
+	Amount int
+}
+
+// Oracle49 implements Challenge 49: a money-transfer API whose requests
+// are authenticated with CBC-MAC under a key shared by client and server.
+// The attacker owns the Attacker account and can get the web client to
+// sign any request from it; Victim is the account to be robbed.
+//
+// v1 requests are message || IV || MAC with
+// message = "from=#{from}&to=#{to}&amount=#{amount}" and a client-chosen IV.
+// v2 requests are message || MAC with a fixed zero IV and
+// message = "from=#{from}&tx_list=#{to:amount(;to:amount)*}".
+type Oracle49 struct {
+	Key      []byte
+	Attacker string
+	Victim   string
+}
+
+func NewOracle49() *Oracle49 {
+	victim := strconv.Itoa(randomInt(1000, 9999))
+	attacker := victim
+	for attacker == victim {
+		attacker = strconv.Itoa(randomInt(1000, 9999))
+	}
+	return &Oracle49{Key: randomBytes(16), Attacker: attacker, Victim: victim}
+}
+
+// TransferV1 is the web client: it signs a v1 transfer from the attacker's
+// own account under a fresh random IV.
+func (o *Oracle49) TransferV1(to string, amount int) []byte {
+	msg := []byte(fmt.Sprintf("from=%s&to=%s&amount=%d", o.Attacker, to, amount))
+	iv := randomBytes(16)
+	mac, _ := cu.CryptoBytes(msg).CBCMAC(o.Key, iv)
+	return append(append(msg, iv...), mac...)
+}
+
+// ProcessV1 is the API server: it checks the MAC of a v1 request and
+// returns the transfer it authorises.
+func (o *Oracle49) ProcessV1(req []byte) (*Transfer, error) {
+	if len(req) < 32 {
+		return nil, errors.ErrInvalidRequest
+	}
+	msg, iv, mac := req[:len(req)-32], req[len(req)-32:len(req)-16], req[len(req)-16:]
+	want, err := cu.CryptoBytes(msg).CBCMAC(o.Key, iv)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(mac, want) != 1 {
+		return nil, errors.ErrInvalidMAC
+	}
+
+	fields := map[string]string{}
+	for _, kv := range strings.Split(string(msg), "&") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, errors.ErrInvalidRequest
+		}
+		fields[k] = v
+	}
+	amount, err := strconv.Atoi(fields["amount"])
+	if err != nil || fields["from"] == "" || fields["to"] == "" {
+		return nil, errors.ErrInvalidRequest
+	}
+	return &Transfer{From: fields["from"], To: fields["to"], Amount: amount}, nil
+}
+
+// TransferV2 is the v2 web client: it signs a transaction list from the
+// attacker's own account.
+func (o *Oracle49) TransferV2(txs []Transfer) []byte {
+	return o.signV2(o.Attacker, txs)
+}
+
+// CaptureV2 returns a request the victim really sent, paying someone 1M
+// spacebucks, as the attacker would sniff it off the wire.
+func (o *Oracle49) CaptureV2() []byte {
+	return o.signV2(o.Victim, []Transfer{{To: "1111", Amount: 1000000}, {To: "2222", Amount: 5}})
+}
+
+func (o *Oracle49) signV2(from string, txs []Transfer) []byte {
+	list := make([]string, len(txs))
+	for i, tx := range txs {
+		list[i] = fmt.Sprintf("%s:%d", tx.To, tx.Amount)
+	}
+	msg := []byte(fmt.Sprintf("from=%s&tx_list=%s", from, strings.Join(list, ";")))
+	mac, _ := cu.CryptoBytes(msg).CBCMAC(o.Key, make([]byte, 16))
+	return append(msg, mac...)
+}
+
+// ProcessV2 is the v2 API server: it checks the MAC under the zero IV and
+// returns the transfers of the list. Like many real parsers it skips
+// transactions it cannot make sense of instead of rejecting the request.
+func (o *Oracle49) ProcessV2(req []byte) ([]Transfer, error) {
+	if len(req) < 16 {
+		return nil, errors.ErrInvalidRequest
+	}
+	msg, mac := req[:len(req)-16], req[len(req)-16:]
+	want, err := cu.CryptoBytes(msg).CBCMAC(o.Key, make([]byte, 16))
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(mac, want) != 1 {
+		return nil, errors.ErrInvalidMAC
+	}
+
+	rest, ok := bytes.CutPrefix(msg, []byte("from="))
+	if !ok {
+		return nil, errors.ErrInvalidRequest
+	}
+	from, list, ok := bytes.Cut(rest, []byte("&tx_list="))
+	if !ok || len(from) == 0 {
+		return nil, errors.ErrInvalidRequest
+	}
+	var txs []Transfer
+	for _, entry := range strings.Split(string(list), ";") {
+		to, amount, ok := strings.Cut(entry, ":")
+		n, err := strconv.Atoi(amount)
+		if !ok || err != nil || to == "" {
+			continue
+		}
+		txs = append(txs, Transfer{From: string(from), To: to, Amount: n})
+	}
+	return txs, nil
+}
@@ -0,0 +1,60 @@
+package oracle
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"sync"
+
+	cu "github.com/jonathanlamela/go-cryptopals/pkg/cryptoutil"
+)
+
+// Oracle51 implements Challenge 51: it wraps attacker-chosen content in an
+// HTTP request carrying a secret session cookie, DEFLATE-compresses the
+// request, encrypts it under a fresh key and IV, and leaks only the length.
+// With CBC set it encrypts with AES-CBC (PKCS#7 padded), otherwise AES-CTR.
+type Oracle51 struct {
+	Session string
+	CBC     bool
+}
+
+// Session51 is the session cookie given in the challenge.
+const Session51 = "TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE="
+
+// NewOracle51 returns an oracle leaking the given base64 session cookie,
+// normally Session51.
+func NewOracle51(session string, cbc bool) *Oracle51 {
+	return &Oracle51{Session: session, CBC: cbc}
+}
+
+// compressors recycles flate writers, which are costly to allocate and
+// the attack asks for many thousands of lengths.
+var compressors = sync.Pool{
+	New: func() any {
+		w, _ := flate.NewWriter(nil, flate.BestCompression)
+		return w
+	},
+}
+
+// Request formats p as the body of a request to hapless.com.
+func (o *Oracle51) Request(p []byte) []byte {
+	return []byte(fmt.Sprintf("POST / HTTP/1.1\nHost: hapless.com\nCookie: sessionid=%s\nContent-Length: %d\n%s", o.Session, len(p), p))
+}
+
+// Length returns the size of the compressed and encrypted request for p.
+func (o *Oracle51) Length(p []byte) int {
+	var buf bytes.Buffer
+	w := compressors.Get().(*flate.Writer)
+	w.Reset(&buf)
+	w.Write(o.Request(p))
+	w.Close()
+	compressors.Put(w)
+
+	var ct []byte
+	if o.CBC {
+		ct, _ = cu.CryptoBytes(buf.Bytes()).SSLCBCEncrypt(randomBytes(16), randomBytes(16), true)
+	} else {
+		ct, _ = cu.CryptoBytes(buf.Bytes()).SSLCTREncrypt(randomBytes(16), randomBytes(16))
+	}
+	return len(ct)
+}
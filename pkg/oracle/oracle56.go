@@ -0,0 +1,20 @@
+package oracle
+
+import "github.com/jonathanlamela/go-cryptopals/pkg/rc4"
+
+// Oracle56 implements Challenge 56: it appends a secret cookie to the
+// attacker's request and RC4-encrypts the result under a fresh random
+// 128-bit key on every call, so only the keystream biases link ciphertexts.
+type Oracle56 struct {
+	Cookie []byte
+}
+
+func NewOracle56(cookie []byte) *Oracle56 {
+	return &Oracle56{Cookie: append([]byte(nil), cookie...)}
+}
+
+// Encrypt returns RC4(random key, request || cookie).
+func (o *Oracle56) Encrypt(request []byte) ([]byte, error) {
+	data := append(append([]byte(nil), request...), o.Cookie...)
+	return rc4.Encrypt(randomBytes(16), data)
+}
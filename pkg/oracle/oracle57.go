@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+)
+
+// Oracle57 implements Bob from Challenges 57-58: given any public key h
+// he computes K = h^x mod p without checking h, and answers with a fixed
+// message and its HMAC-SHA256 under K. An h of small order r confines K to
+// r values, each of which the MAC lets the attacker test offline.
+type Oracle57 struct {
+	Key     *dh.KeyPair
+	Message []byte
+}
+
+func NewOracle57(group *dh.Group) (*Oracle57, error) {
+	k, err := group.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle57{Key: k, Message: []byte("crazy flamboyant for the rap enjoyment")}, nil
+}
+
+// Group returns the public parameters Bob works in.
+func (o *Oracle57) Group() *dh.Group { return o.Key.Group }
+
+// PublicKey returns Bob's public key g^x mod p.
+func (o *Oracle57) PublicKey() *big.Int { return new(big.Int).Set(o.Key.Public) }
+
+// Respond returns Bob's message and its MAC under the secret shared with h.
+func (o *Oracle57) Respond(h *big.Int) ([]byte, []byte) {
+	return o.Message, MAC57(o.Key.SharedSecret(h), o.Message)
+}
+
+// MAC57 is the MAC Bob uses: HMAC-SHA256 keyed with the shared secret's
+// big-endian bytes.
+func MAC57(k *big.Int, msg []byte) []byte {
+	mac := hmac.New(sha256.New, k.Bytes())
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
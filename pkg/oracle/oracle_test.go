@@ -1,7 +1,15 @@
 package oracle
 
 import (
+	"crypto/hmac"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
 )
 
 func TestNewOracle11(t *testing.T) {
@@ -264,3 +272,245 @@ func TestRandomBytesAndInt(t *testing.T) {
 		t.Errorf("randomInt(50, 50) should return 50, got %d", i3)
 	}
 }
+
+func TestOracle28MAC(t *testing.T) {
+	o := NewOracle28()
+	if len(o.Key) < 1 || len(o.Key) > 64 {
+		t.Errorf("Oracle28.Key length = %d, want between 1 and 64", len(o.Key))
+	}
+	msg, mac := o.Message()
+	if len(mac) != 20 {
+		t.Errorf("MAC length = %d, want 20", len(mac))
+	}
+	if !o.Verify(msg, mac) {
+		t.Error("Verify() should accept the oracle's own MAC")
+	}
+	tampered := append([]byte(nil), msg...)
+	tampered[0] ^= 1
+	if o.Verify(tampered, mac) {
+		t.Error("Verify() should reject a tampered message")
+	}
+	if o.IsAdmin(msg, mac) {
+		t.Error("IsAdmin() should reject a message without ;admin=true")
+	}
+}
+
+func TestOracle30MAC(t *testing.T) {
+	o := NewOracle30()
+	msg, mac := o.Message()
+	if len(mac) != 16 {
+		t.Errorf("MAC length = %d, want 16", len(mac))
+	}
+	if !o.Verify(msg, mac) {
+		t.Error("Verify() should accept the oracle's own MAC")
+	}
+	if o.IsAdmin(msg, mac) {
+		t.Error("IsAdmin() should reject a message without ;admin=true")
+	}
+}
+
+func TestOracle31ServeHTTP(t *testing.T) {
+	o, err := NewOracle31(0, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(o)
+	defer srv.Close()
+
+	good := hex.EncodeToString(o.HMAC([]byte("foo")))
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{name: "valid signature", query: "?file=foo&signature=" + good, wantStatus: http.StatusOK},
+		{name: "wrong file", query: "?file=bar&signature=" + good, wantStatus: http.StatusInternalServerError},
+		{name: "truncated signature", query: "?file=foo&signature=" + good[:10], wantStatus: http.StatusInternalServerError},
+		{name: "bad hex", query: "?file=foo&signature=zz", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNewOracle31SigLen(t *testing.T) {
+	for _, n := range []int{0, 21} {
+		if _, err := NewOracle31(0, n); err == nil {
+			t.Errorf("NewOracle31() accepted SigLen %d", n)
+		}
+	}
+}
+
+func TestInsecureCompareTiming(t *testing.T) {
+	a := []byte{1, 2, 3, 4}
+	start := time.Now()
+	insecureCompare(a, []byte{9, 2, 3, 4}, 5*time.Millisecond)
+	early := time.Since(start)
+	start = time.Now()
+	if !insecureCompare(a, a, 5*time.Millisecond) {
+		t.Fatal("insecureCompare() should accept equal slices")
+	}
+	full := time.Since(start)
+	if full < 20*time.Millisecond || early >= full {
+		t.Errorf("insecureCompare() timing does not leak: early %v, full %v", early, full)
+	}
+}
+
+func TestOracle41Decrypt(t *testing.T) {
+	o, err := NewOracle41()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := o.PublicKey().EncryptBytes([]byte("{time: 1356304276, social: '555-55-5555'}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := o.Decrypt(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pt) != "{time: 1356304276, social: '555-55-5555'}" {
+		t.Errorf("Decrypt() = %q", pt)
+	}
+	if _, err := o.Decrypt(ct); err == nil {
+		t.Error("Decrypt() should refuse a replayed ciphertext")
+	}
+	if _, err := o.Decrypt(append([]byte{0}, ct...)); err == nil {
+		t.Error("Decrypt() should refuse a zero-prefixed replay")
+	}
+	if _, err := o.Decrypt(new(big.Int).SetBytes(ct).Bytes()); err == nil {
+		t.Error("Decrypt() should refuse a replay with leading zeros stripped")
+	}
+}
+
+func TestOracle46IsEven(t *testing.T) {
+	o, err := NewOracle46()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []int64{2, 3, 1000, 1001} {
+		ct := o.PublicKey().Encrypt(big.NewInt(m)).Bytes()
+		even, err := o.IsEven(ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if even != (m%2 == 0) {
+			t.Errorf("IsEven(%d) = %v", m, even)
+		}
+	}
+	if _, err := o.IsEven(o.Key.N.Bytes()); err == nil {
+		t.Error("IsEven() should reject a ciphertext >= N")
+	}
+}
+
+func TestOracle47PaddingOK(t *testing.T) {
+	o, err := NewOracle47(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := o.PublicKey().EncryptPKCS1v15([]byte("kick it, CC"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.PaddingOK(ct) {
+		t.Error("PaddingOK() rejected a conforming ciphertext")
+	}
+	if o.PaddingOK(o.PublicKey().Encrypt(big.NewInt(42)).Bytes()) {
+		t.Error("PaddingOK() accepted a non-conforming ciphertext")
+	}
+	if o.Queries != 2 {
+		t.Errorf("Queries = %d, want 2", o.Queries)
+	}
+}
+
+func TestOracle49V1(t *testing.T) {
+	o := NewOracle49()
+	req := o.TransferV1("1234", 50)
+	tx, err := o.ProcessV1(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *tx != (Transfer{From: o.Attacker, To: "1234", Amount: 50}) {
+		t.Errorf("ProcessV1() = %+v", tx)
+	}
+	req[0] ^= 1
+	if _, err := o.ProcessV1(req); err == nil {
+		t.Error("ProcessV1() accepted a tampered request")
+	}
+}
+
+func TestOracle49V2(t *testing.T) {
+	o := NewOracle49()
+	txs, err := o.ProcessV2(o.TransferV2([]Transfer{{To: "1234", Amount: 50}, {To: "5678", Amount: 7}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Transfer{{From: o.Attacker, To: "1234", Amount: 50}, {From: o.Attacker, To: "5678", Amount: 7}}
+	if len(txs) != len(want) || txs[0] != want[0] || txs[1] != want[1] {
+		t.Errorf("ProcessV2() = %+v, want %+v", txs, want)
+	}
+
+	req := o.CaptureV2()
+	req[len(req)-1] ^= 1
+	if _, err := o.ProcessV2(req); err == nil {
+		t.Error("ProcessV2() accepted a tampered MAC")
+	}
+}
+
+func TestOracle51Length(t *testing.T) {
+	ctr, cbc := NewOracle51(Session51, false), NewOracle51(Session51, true)
+	match := []byte("sessionid=" + ctr.Session)
+	miss := []byte("sessionid=" + "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopq")
+	if ctr.Length(match) >= ctr.Length(miss) {
+		t.Error("repeating the cookie should compress better")
+	}
+	if ctr.Length(match) != ctr.Length(match) {
+		t.Error("Length() should only depend on the content")
+	}
+	if n := cbc.Length(miss); n%16 != 0 {
+		t.Errorf("CBC Length() = %d, want a multiple of 16", n)
+	}
+}
+
+func TestOracle56Encrypt(t *testing.T) {
+	cookie := []byte("secret cookie")
+	o := NewOracle56(cookie)
+	a, err := o.Encrypt([]byte("AA"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	b, _ := o.Encrypt([]byte("AA"))
+	if len(a) != 2+len(cookie) {
+		t.Errorf("Encrypt() length = %d, want %d", len(a), 2+len(cookie))
+	}
+	if hex.EncodeToString(a) == hex.EncodeToString(b) {
+		t.Error("Encrypt() should use a fresh key per call")
+	}
+}
+
+func TestOracle57Respond(t *testing.T) {
+	o, err := NewOracle57(dh.Challenge57Group())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Answering Bob's own public key g^x gives K = g^(x^2)
+	msg, mac := o.Respond(o.PublicKey())
+	k := dh.ModExp(o.PublicKey(), o.Key.Private, o.Group().P)
+	if !hmac.Equal(mac, MAC57(k, msg)) {
+		t.Error("Respond() MAC does not match the shared secret")
+	}
+	// The identity confines the secret to K = 1
+	if _, mac := o.Respond(big.NewInt(1)); !hmac.Equal(mac, MAC57(big.NewInt(1), msg)) {
+		t.Error("Respond(1) should MAC under K = 1")
+	}
+}
@@ -0,0 +1,52 @@
+package rc4
+
+import "github.com/jonathanlamela/go-cryptopals/pkg/errors"
+
+// Cipher is an RC4 keystream generator. Its early output bytes are biased,
+// which is what Challenge 56 exploits.
+type Cipher struct {
+	s    [256]byte
+	i, j uint8
+}
+
+// NewCipher runs the RC4 key schedule for a key of 1 to 256 bytes.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) < 1 || len(key) > 256 {
+		return nil, errors.ErrBadKeySize
+	}
+	c := &Cipher{}
+	for i := range c.s {
+		c.s[i] = byte(i)
+	}
+	var j uint8
+	for i := range c.s {
+		j += c.s[i] + key[i%len(key)]
+		c.s[i], c.s[j] = c.s[j], c.s[i]
+	}
+	return c, nil
+}
+
+// XORKeyStream XORs src with the next len(src) keystream bytes into dst,
+// which must be at least as long as src. dst and src may overlap exactly.
+func (c *Cipher) XORKeyStream(dst, src []byte) {
+	i, j := c.i, c.j
+	for k, v := range src {
+		i++
+		j += c.s[i]
+		c.s[i], c.s[j] = c.s[j], c.s[i]
+		dst[k] = v ^ c.s[c.s[i]+c.s[j]]
+	}
+	c.i, c.j = i, j
+}
+
+// Encrypt returns msg XORed with the keystream of a fresh cipher under key.
+// Decryption is the same operation.
+func Encrypt(key, msg []byte) ([]byte, error) {
+	c, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(msg))
+	c.XORKeyStream(out, msg)
+	return out, nil
+}
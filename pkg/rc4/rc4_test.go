@@ -0,0 +1,53 @@
+package rc4
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptVectors(t *testing.T) {
+	tests := []struct {
+		key, plaintext, want string
+	}{
+		{"Key", "Plaintext", "bbf316e8d940af0ad3"},
+		{"Wiki", "pedia", "1021bf0420"},
+		{"Secret", "Attack at dawn", "45a01f645fc35b383552544b9bf5"},
+	}
+	for _, tt := range tests {
+		got, err := Encrypt([]byte(tt.key), []byte(tt.plaintext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("Encrypt(%q, %q) = %x, want %s", tt.key, tt.plaintext, got, tt.want)
+		}
+	}
+}
+
+func TestXORKeyStreamIncremental(t *testing.T) {
+	key := []byte{1, 2, 3, 4, 5}
+	// RFC 6229, 40-bit key, keystream at offset 0
+	want, _ := hex.DecodeString("b2396305f03dc027ccc3524a0a1118a8")
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, len(want))
+	for i := 0; i < len(got); i += 3 {
+		end := min(i+3, len(got))
+		c.XORKeyStream(got[i:end], got[i:end])
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("keystream = %x, want %x", got, want)
+	}
+}
+
+func TestNewCipherKeySize(t *testing.T) {
+	if _, err := NewCipher(nil); err == nil {
+		t.Error("NewCipher(nil) should fail")
+	}
+	if _, err := NewCipher(make([]byte, 257)); err == nil {
+		t.Error("NewCipher() with a 257-byte key should fail")
+	}
+}
@@ -0,0 +1,163 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// digestInfoPrefixes holds the DER-encoded ASN.1 DigestInfo header that
+// precedes the hash in a PKCS#1 v1.5 signature (RFC 8017, section 9.2).
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+}
+
+// DigestInfo returns the ASN.1 DigestInfo for digest: the hash's DER prefix followed by the digest.
+func DigestInfo(h crypto.Hash, digest []byte) ([]byte, error) {
+	prefix, ok := digestInfoPrefixes[h]
+	if !ok || len(digest) != h.Size() {
+		return nil, errors.ErrUnsupportedHash
+	}
+	return append(append([]byte(nil), prefix...), digest...), nil
+}
+
+// signatureBlock builds the k-byte encoded message 00 01 FF..FF 00 DigestInfo.
+func signatureBlock(h crypto.Hash, digest []byte, k int) ([]byte, error) {
+	info, err := DigestInfo(h, digest)
+	if err != nil {
+		return nil, err
+	}
+	// At least eight bytes of 0xFF padding are required
+	if len(info)+11 > k {
+		return nil, errors.ErrMessageTooLong
+	}
+	em := make([]byte, k)
+	em[1] = 0x01
+	for i := 2; i < k-len(info)-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[k-len(info):], info)
+	return em, nil
+}
+
+// SignPKCS1v15 signs digest, the output of hash h, with PKCS#1 v1.5 padding.
+func (k *PrivateKey) SignPKCS1v15(h crypto.Hash, digest []byte) ([]byte, error) {
+	em, err := signatureBlock(h, digest, k.Size())
+	if err != nil {
+		return nil, err
+	}
+	s := k.Decrypt(new(big.Int).SetBytes(em))
+	return s.FillBytes(make([]byte, k.Size())), nil
+}
+
+// encodedMessage opens sig with the public key and returns the k-byte block.
+func (k *PublicKey) encodedMessage(sig []byte) ([]byte, error) {
+	s := new(big.Int).SetBytes(sig)
+	if len(sig) != k.Size() || s.Cmp(k.N) >= 0 {
+		return nil, errors.ErrVerificationFailed
+	}
+	return k.Encrypt(s).FillBytes(make([]byte, k.Size())), nil
+}
+
+// VerifyPKCS1v15 is the strict verifier: it rebuilds the whole expected
+// block, padding included, and compares it byte for byte.
+func (k *PublicKey) VerifyPKCS1v15(h crypto.Hash, digest, sig []byte) error {
+	want, err := signatureBlock(h, digest, k.Size())
+	if err != nil {
+		return err
+	}
+	em, err := k.encodedMessage(sig)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(em, want) {
+		return errors.ErrVerificationFailed
+	}
+	return nil
+}
+
+// VerifyPKCS1v15Sloppy is the deliberately broken verifier of Challenge 42.
+// It walks 00 01, any run of FF, 00 and the DigestInfo from the left, but
+// never checks that the hash ends the block, so trailing garbage passes.
+func (k *PublicKey) VerifyPKCS1v15Sloppy(h crypto.Hash, digest, sig []byte) error {
+	info, err := DigestInfo(h, digest)
+	if err != nil {
+		return err
+	}
+	em, err := k.encodedMessage(sig)
+	if err != nil {
+		return err
+	}
+	if em[0] != 0x00 || em[1] != 0x01 {
+		return errors.ErrVerificationFailed
+	}
+	i := 2
+	for i < len(em) && em[i] == 0xff {
+		i++
+	}
+	if i == len(em) || em[i] != 0x00 {
+		return errors.ErrVerificationFailed
+	}
+	if !bytes.HasPrefix(em[i+1:], info) {
+		return errors.ErrVerificationFailed
+	}
+	return nil
+}
+
+// PadPKCS1v15 builds the k-byte encryption block 00 02 PS 00 msg, where PS is
+// at least eight random non-zero bytes (RFC 8017, section 7.2.1).
+func PadPKCS1v15(msg []byte, k int) ([]byte, error) {
+	if len(msg)+11 > k {
+		return nil, errors.ErrMessageTooLong
+	}
+	em := make([]byte, k)
+	em[1] = 0x02
+	ps := em[2 : k-len(msg)-1]
+	if _, err := rand.Read(ps); err != nil {
+		return nil, err
+	}
+	for i := range ps {
+		for ps[i] == 0 {
+			if _, err := rand.Read(ps[i : i+1]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	copy(em[k-len(msg):], msg)
+	return em, nil
+}
+
+// UnpadPKCS1v15 reverses PadPKCS1v15, returning the message after the first
+// zero byte that follows at least eight bytes of padding.
+func UnpadPKCS1v15(em []byte) ([]byte, error) {
+	if len(em) < 11 || em[0] != 0x00 || em[1] != 0x02 {
+		return nil, errors.ErrInvalidPadding
+	}
+	i := bytes.IndexByte(em[2:], 0x00)
+	if i < 8 {
+		return nil, errors.ErrInvalidPadding
+	}
+	return em[2+i+1:], nil
+}
+
+// EncryptPKCS1v15 pads msg with PadPKCS1v15 and encrypts it.
+func (k *PublicKey) EncryptPKCS1v15(msg []byte) ([]byte, error) {
+	em, err := PadPKCS1v15(msg, k.Size())
+	if err != nil {
+		return nil, err
+	}
+	return k.Encrypt(new(big.Int).SetBytes(em)).FillBytes(make([]byte, k.Size())), nil
+}
+
+// DecryptPKCS1v15 decrypts ct and strips its PKCS#1 v1.5 encryption padding.
+func (k *PrivateKey) DecryptPKCS1v15(ct []byte) ([]byte, error) {
+	c := new(big.Int).SetBytes(ct)
+	if len(ct) != k.Size() || c.Cmp(k.N) >= 0 {
+		return nil, errors.ErrMessageTooLong
+	}
+	return UnpadPKCS1v15(k.Decrypt(c).FillBytes(make([]byte, k.Size())))
+}
@@ -0,0 +1,174 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// PublicKey is a textbook RSA public key.
+type PublicKey struct {
+	N *big.Int
+	E *big.Int
+}
+
+// PrivateKey is a textbook RSA private key with its factors.
+type PrivateKey struct {
+	PublicKey
+	D    *big.Int
+	P, Q *big.Int
+
+	// CRT values filled in by GenerateKey: d mod (p-1), d mod (q-1), q^-1 mod p
+	dp, dq, qInv *big.Int
+}
+
+// InvMod returns the inverse of a modulo m using the extended Euclidean algorithm.
+// It keeps only the coefficient of a: each step maintains old_r = old_s*a (mod m).
+func InvMod(a, m *big.Int) (*big.Int, error) {
+	oldR, r := new(big.Int).Mod(a, m), new(big.Int).Set(m)
+	oldS, s := big.NewInt(1), big.NewInt(0)
+	q, tmp := new(big.Int), new(big.Int)
+	for r.Sign() != 0 {
+		q.Div(oldR, r)
+		// (old_r, r) = (r, old_r - q*r)
+		tmp.Mul(q, r)
+		oldR, r = r, new(big.Int).Sub(oldR, tmp)
+		// (old_s, s) = (s, old_s - q*s)
+		tmp.Mul(q, s)
+		oldS, s = s, new(big.Int).Sub(oldS, tmp)
+	}
+	if oldR.Cmp(big.NewInt(1)) != 0 {
+		return nil, errors.ErrNoInverse
+	}
+	return oldS.Mod(oldS, m), nil
+}
+
+// GeneratePrime returns a random prime of exactly bits bits.
+func GeneratePrime(bits int) (*big.Int, error) {
+	return rand.Prime(rand.Reader, bits)
+}
+
+// GenerateKey generates a key pair with a bits-bit modulus and public exponent e.
+// Primes are redrawn until e is invertible modulo (p-1)(q-1), which matters for e=3.
+func GenerateKey(bits int, e int64) (*PrivateKey, error) {
+	E := big.NewInt(e)
+	one := big.NewInt(1)
+	for {
+		p, err := GeneratePrime(bits / 2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := GeneratePrime(bits - bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		n := new(big.Int).Mul(p, q)
+		et := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		d, err := InvMod(E, et)
+		if err != nil {
+			continue
+		}
+		k := &PrivateKey{PublicKey: PublicKey{N: n, E: E}, D: d, P: p, Q: q}
+		if k.qInv, err = InvMod(q, p); err != nil {
+			continue
+		}
+		k.dp = new(big.Int).Mod(d, new(big.Int).Sub(p, one))
+		k.dq = new(big.Int).Mod(d, new(big.Int).Sub(q, one))
+		return k, nil
+	}
+}
+
+// Size returns the modulus length in bytes.
+func (k *PublicKey) Size() int { return (k.N.BitLen() + 7) / 8 }
+
+// Encrypt computes m^e mod N.
+func (k *PublicKey) Encrypt(m *big.Int) *big.Int {
+	return new(big.Int).Exp(m, k.E, k.N)
+}
+
+// Decrypt computes c^d mod N. Keys from GenerateKey work mod P and mod Q
+// separately and recombine with Garner's formula, which is several times
+// faster; the padding oracles lean on that.
+func (k *PrivateKey) Decrypt(c *big.Int) *big.Int {
+	if k.qInv == nil {
+		return new(big.Int).Exp(c, k.D, k.N)
+	}
+	mp := new(big.Int).Exp(c, k.dp, k.P)
+	mq := new(big.Int).Exp(c, k.dq, k.Q)
+
+	// m = mq + Q * ((mp - mq) * Q^-1 mod P)
+	h := mp.Sub(mp, mq)
+	h.Mul(h, k.qInv).Mod(h, k.P)
+	return h.Mul(h, k.Q).Add(h, mq)
+}
+
+// EncryptBytes reads msg as a big-endian integer, encrypts it and returns
+// the ciphertext left-padded to the modulus size.
+func (k *PublicKey) EncryptBytes(msg []byte) ([]byte, error) {
+	m := new(big.Int).SetBytes(msg)
+	if m.Cmp(k.N) >= 0 {
+		return nil, errors.ErrMessageTooLong
+	}
+	return k.Encrypt(m).FillBytes(make([]byte, k.Size())), nil
+}
+
+// DecryptBytes reverses EncryptBytes. Being textbook RSA, leading zero
+// bytes of the original message are not recoverable and are dropped.
+func (k *PrivateKey) DecryptBytes(ct []byte) ([]byte, error) {
+	c := new(big.Int).SetBytes(ct)
+	if c.Cmp(k.N) >= 0 {
+		return nil, errors.ErrMessageTooLong
+	}
+	return k.Decrypt(c).Bytes(), nil
+}
+
+// NthRoot returns the integer n-th root of a (floor of a^(1/n)) for a >= 0
+// and whether the root is exact. It runs Newton's iteration
+// x' = ((n-1)x + a/x^(n-1)) / n from a power of two above the root;
+// the sequence decreases monotonically until it reaches the floor.
+func NthRoot(a *big.Int, n int) (*big.Int, bool) {
+	if a.Sign() == 0 {
+		return big.NewInt(0), true
+	}
+	N := big.NewInt(int64(n))
+	N1 := big.NewInt(int64(n - 1))
+	x := new(big.Int).Lsh(big.NewInt(1), uint(a.BitLen()/n+1))
+	for {
+		// y = ((n-1)*x + a / x^(n-1)) / n
+		y := new(big.Int).Exp(x, N1, nil)
+		y.Div(a, y)
+		y.Add(y, new(big.Int).Mul(N1, x))
+		y.Div(y, N)
+		if y.Cmp(x) >= 0 {
+			break
+		}
+		x = y
+	}
+	return x, new(big.Int).Exp(x, N, nil).Cmp(a) == 0
+}
+
+// CRT returns the unique x modulo the product of moduli with
+// x = residues[i] mod moduli[i] for every i. Moduli must be pairwise coprime.
+func CRT(residues, moduli []*big.Int) (*big.Int, error) {
+	prod := big.NewInt(1)
+	for _, m := range moduli {
+		prod.Mul(prod, m)
+	}
+	x := new(big.Int)
+	for i, m := range moduli {
+		// ms = product of all the other moduli
+		ms := new(big.Int).Div(prod, m)
+		inv, err := InvMod(ms, m)
+		if err != nil {
+			return nil, err
+		}
+		term := new(big.Int).Mul(residues[i], ms)
+		term.Mul(term, inv)
+		x.Add(x, term)
+	}
+	return x.Mod(x, prod), nil
+}
@@ -0,0 +1,280 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	b64 "github.com/jonathanlamela/go-cryptopals/pkg/base64"
+	hex "github.com/jonathanlamela/go-cryptopals/pkg/hex"
+)
+
+func TestInvMod(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, m    int64
+		want    int64
+		wantErr bool
+	}{
+		{name: "challenge example", a: 17, m: 3120, want: 2753},
+		{name: "small", a: 3, m: 11, want: 4},
+		{name: "a larger than m", a: 25, m: 7, want: 2},
+		{name: "not coprime", a: 6, m: 9, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InvMod(big.NewInt(tt.a), big.NewInt(tt.m))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InvMod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Int64() != tt.want {
+				t.Errorf("InvMod() = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateKeyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		bits int
+		e    int64
+	}{
+		{name: "e=3", bits: 512, e: 3},
+		{name: "e=65537", bits: 512, e: 65537},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := GenerateKey(tt.bits, tt.e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if k.N.BitLen() != tt.bits {
+				t.Errorf("N bit length = %d, want %d", k.N.BitLen(), tt.bits)
+			}
+			msg := []byte("textbook RSA round trip")
+			ct, err := k.EncryptBytes(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ct) != k.Size() {
+				t.Errorf("ciphertext length = %d, want %d", len(ct), k.Size())
+			}
+			pt, err := k.DecryptBytes(ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(pt) != string(msg) {
+				t.Errorf("DecryptBytes() = %q, want %q", pt, msg)
+			}
+		})
+	}
+}
+
+func TestEncryptBytesTooLong(t *testing.T) {
+	k, err := GenerateKey(256, 65537)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.EncryptBytes(make([]byte, 40)); err != nil {
+		t.Errorf("EncryptBytes() of zeros should fit: %v", err)
+	}
+	long := make([]byte, 40)
+	long[0] = 1
+	if _, err := k.EncryptBytes(long); err == nil {
+		t.Error("EncryptBytes() should reject messages >= N")
+	}
+}
+
+func TestEncodingInterop(t *testing.T) {
+	k, err := GenerateKey(512, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("hex and base64 survive the trip")
+	ct, err := k.EncryptBytes(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, _ := hex.FromBytes(ct)
+	parsed, err := hex.FromString(h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromHex, err := parsed.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromB64, err := b64.FromString(b64.FromBytes(ct).String()).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, c := range map[string][]byte{"hex": fromHex, "base64": fromB64} {
+		pt, err := k.DecryptBytes(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(pt) != string(msg) {
+			t.Errorf("%s round trip = %q, want %q", name, pt, msg)
+		}
+	}
+}
+
+func TestNthRoot(t *testing.T) {
+	big135, _ := new(big.Int).SetString("1000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", 10)
+	tests := []struct {
+		name      string
+		a         *big.Int
+		n         int
+		want      int64
+		wantExact bool
+	}{
+		{name: "zero", a: big.NewInt(0), n: 3, want: 0, wantExact: true},
+		{name: "one", a: big.NewInt(1), n: 5, want: 1, wantExact: true},
+		{name: "perfect cube", a: big.NewInt(27), n: 3, want: 3, wantExact: true},
+		{name: "floor of cube root", a: big.NewInt(30), n: 3, want: 3},
+		{name: "square root", a: big.NewInt(1 << 40), n: 2, want: 1 << 20, wantExact: true},
+		{name: "just below a cube", a: big.NewInt(63), n: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exact := NthRoot(tt.a, tt.n)
+			if got.Int64() != tt.want || exact != tt.wantExact {
+				t.Errorf("NthRoot(%v, %d) = %v, %v; want %d, %v", tt.a, tt.n, got, exact, tt.want, tt.wantExact)
+			}
+		})
+	}
+
+	// 10^135 is (10^45)^3
+	root, exact := NthRoot(big135, 3)
+	want := new(big.Int).Exp(big.NewInt(10), big.NewInt(45), nil)
+	if !exact || root.Cmp(want) != 0 {
+		t.Errorf("NthRoot(10^135, 3) = %v, %v; want 10^45", root, exact)
+	}
+}
+
+func TestCRT(t *testing.T) {
+	// x = 2 mod 3, x = 3 mod 5, x = 2 mod 7 -> x = 23
+	residues := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(2)}
+	moduli := []*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(7)}
+	got, err := CRT(residues, moduli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Int64() != 23 {
+		t.Errorf("CRT() = %v, want 23", got)
+	}
+
+	if _, err := CRT(residues[:2], []*big.Int{big.NewInt(4), big.NewInt(6)}); err == nil {
+		t.Error("CRT() should fail for non-coprime moduli")
+	}
+}
+
+func TestPKCS1v15SignVerify(t *testing.T) {
+	k, err := GenerateKey(1024, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		h    crypto.Hash
+		sum  []byte
+	}{
+		{name: "sha1", h: crypto.SHA1, sum: sha1Sum("hi mom")},
+		{name: "sha256", h: crypto.SHA256, sum: sha256Sum("hi mom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := k.SignPKCS1v15(tt.h, tt.sum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := k.VerifyPKCS1v15(tt.h, tt.sum, sig); err != nil {
+				t.Errorf("VerifyPKCS1v15() error = %v", err)
+			}
+			if err := k.VerifyPKCS1v15Sloppy(tt.h, tt.sum, sig); err != nil {
+				t.Errorf("VerifyPKCS1v15Sloppy() error = %v", err)
+			}
+			sig[len(sig)-1] ^= 1
+			if err := k.VerifyPKCS1v15(tt.h, tt.sum, sig); err == nil {
+				t.Error("VerifyPKCS1v15() accepted a corrupted signature")
+			}
+		})
+	}
+
+	if _, err := k.SignPKCS1v15(crypto.MD5, make([]byte, 16)); err == nil {
+		t.Error("SignPKCS1v15() should reject an unsupported hash")
+	}
+}
+
+func TestPKCS1v15SloppyAcceptsTrailingGarbage(t *testing.T) {
+	k, err := GenerateKey(1024, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1Sum("hi mom")
+	info, _ := DigestInfo(crypto.SHA1, sum)
+	// 00 01 FF 00 DigestInfo, then garbage instead of right-justification
+	em := make([]byte, k.Size())
+	copy(em, append([]byte{0x00, 0x01, 0xff, 0x00}, info...))
+	for i := 4 + len(info); i < len(em); i++ {
+		em[i] = 0xab
+	}
+	sig := k.Decrypt(new(big.Int).SetBytes(em)).FillBytes(make([]byte, k.Size()))
+
+	if err := k.VerifyPKCS1v15Sloppy(crypto.SHA1, sum, sig); err != nil {
+		t.Errorf("VerifyPKCS1v15Sloppy() error = %v", err)
+	}
+	if err := k.VerifyPKCS1v15(crypto.SHA1, sum, sig); err == nil {
+		t.Error("VerifyPKCS1v15() accepted trailing garbage")
+	}
+}
+
+func TestPKCS1v15EncryptDecrypt(t *testing.T) {
+	k, err := GenerateKey(512, 65537)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("kick it, CC")
+	ct, err := k.EncryptPKCS1v15(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := k.DecryptPKCS1v15(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pt, msg) {
+		t.Errorf("DecryptPKCS1v15() = %q, want %q", pt, msg)
+	}
+
+	em := k.Decrypt(new(big.Int).SetBytes(ct)).FillBytes(make([]byte, k.Size()))
+	if em[0] != 0x00 || em[1] != 0x02 || bytes.IndexByte(em[2:len(em)-len(msg)-1], 0x00) >= 0 {
+		t.Errorf("bad padding block %x", em)
+	}
+	if _, err := k.EncryptPKCS1v15(make([]byte, k.Size()-10)); err == nil {
+		t.Error("EncryptPKCS1v15() should reject a message without room for padding")
+	}
+	if _, err := UnpadPKCS1v15(append([]byte{0x00, 0x02, 0x01, 0x00}, make([]byte, 60)...)); err == nil {
+		t.Error("UnpadPKCS1v15() accepted fewer than eight padding bytes")
+	}
+}
+
+func sha1Sum(s string) []byte {
+	sum := sha1.Sum([]byte(s))
+	return sum[:]
+}
+
+func sha256Sum(s string) []byte {
+	sum := sha256.Sum256([]byte(s))
+	return sum[:]
+}
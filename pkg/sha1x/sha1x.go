@@ -0,0 +1,152 @@
+package sha1x
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Size is the size of a SHA-1 checksum in bytes.
+const Size = 20
+
+// BlockSize is the block size of SHA-1 in bytes.
+const BlockSize = 64
+
+// initState is the standard SHA-1 initialization vector (FIPS 180-4, 5.3.1).
+var initState = [5]uint32{0x67452301, 0xEFCDAB89, 0x98BADCFE, 0x10325476, 0xC3D2E1F0}
+
+// Digest is a pure-Go SHA-1 implementation.
+// Unlike crypto/sha1 its internal state can be injected with NewFromState,
+// which is exactly what a length-extension attack needs.
+type Digest struct {
+	h   [5]uint32
+	buf [BlockSize]byte
+	nx  int
+	len uint64
+
+	initH   [5]uint32
+	initLen uint64
+}
+
+// New returns a SHA-1 hash starting from the standard initialization vector.
+func New() *Digest {
+	return NewFromState(initState, 0)
+}
+
+// NewFromState returns a SHA-1 hash whose registers are set to h and which
+// believes length bytes have already been processed.
+// length must be a multiple of BlockSize for the result to be meaningful:
+// it is the length of the original message including its glue padding.
+func NewFromState(h [5]uint32, length uint64) *Digest {
+	d := &Digest{initH: h, initLen: length}
+	d.Reset()
+	return d
+}
+
+// Sum returns the SHA-1 checksum of data.
+func Sum(data []byte) [Size]byte {
+	d := New()
+	d.Write(data)
+	var out [Size]byte
+	copy(out[:], d.Sum(nil))
+	return out
+}
+
+// StateFromSum splits a SHA-1 checksum back into its five big-endian registers.
+func StateFromSum(sum []byte) [5]uint32 {
+	var h [5]uint32
+	for i := range h {
+		h[i] = binary.BigEndian.Uint32(sum[4*i:])
+	}
+	return h
+}
+
+// Padding returns the MD-strengthening padding SHA-1 appends to a message of
+// msgLen bytes: 0x80, zeros up to 56 mod 64, then the bit length as a big-endian uint64.
+func Padding(msgLen uint64) []byte {
+	padLen := BlockSize - int((msgLen+8)%BlockSize)
+	if padLen == 0 {
+		padLen = BlockSize
+	}
+	out := make([]byte, padLen+8)
+	out[0] = 0x80
+	binary.BigEndian.PutUint64(out[padLen:], msgLen<<3)
+	return out
+}
+
+var _ hash.Hash = (*Digest)(nil)
+
+func (d *Digest) Reset() {
+	d.h = d.initH
+	d.nx = 0
+	d.len = d.initLen
+}
+
+func (d *Digest) Size() int      { return Size }
+func (d *Digest) BlockSize() int { return BlockSize }
+
+func (d *Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		c := copy(d.buf[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+		if d.nx == BlockSize {
+			block(&d.h, d.buf[:])
+			d.nx = 0
+		}
+	}
+	for len(p) >= BlockSize {
+		block(&d.h, p[:BlockSize])
+		p = p[BlockSize:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.buf[:], p)
+	}
+	return n, nil
+}
+
+// Sum appends the current checksum to b without changing the digest state.
+func (d *Digest) Sum(b []byte) []byte {
+	dup := *d
+	dup.Write(Padding(d.len))
+	out := make([]byte, Size)
+	for i, v := range dup.h {
+		binary.BigEndian.PutUint32(out[4*i:], v)
+	}
+	return append(b, out...)
+}
+
+// block runs the SHA-1 compression function over one 64-byte block.
+func block(h *[5]uint32, p []byte) {
+	var w [80]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(p[4*i:])
+	}
+	for i := 16; i < 80; i++ {
+		w[i] = bits.RotateLeft32(w[i-3]^w[i-8]^w[i-14]^w[i-16], 1)
+	}
+
+	a, b, c, d, e := h[0], h[1], h[2], h[3], h[4]
+	for i := 0; i < 80; i++ {
+		var f, k uint32
+		switch {
+		case i < 20:
+			f, k = (b&c)|(^b&d), 0x5A827999
+		case i < 40:
+			f, k = b^c^d, 0x6ED9EBA1
+		case i < 60:
+			f, k = (b&c)|(b&d)|(c&d), 0x8F1BBCDC
+		default:
+			f, k = b^c^d, 0xCA62C1D6
+		}
+		t := bits.RotateLeft32(a, 5) + f + e + k + w[i]
+		e, d, c, b, a = d, c, bits.RotateLeft32(b, 30), a, t
+	}
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+	h[4] += e
+}
@@ -0,0 +1,82 @@
+package sha1x
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{name: "empty", input: []byte{}},
+		{name: "abc", input: []byte("abc")},
+		{name: "one block boundary", input: bytes.Repeat([]byte("a"), 55)},
+		{name: "two blocks", input: bytes.Repeat([]byte("a"), 56)},
+		{name: "long", input: bytes.Repeat([]byte("The quick brown fox "), 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sum(tt.input)
+			want := sha1.Sum(tt.input)
+			if got != want {
+				t.Errorf("Sum() = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestDigestIncrementalWrite(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 30)
+	d := New()
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		d.Write(data[i:end])
+	}
+	want := sha1.Sum(data)
+	if got := d.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("Sum() = %x, want %x", got, want)
+	}
+	// Sum must not disturb the running state
+	if got := d.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("second Sum() = %x, want %x", got, want)
+	}
+	d.Reset()
+	empty := sha1.Sum(nil)
+	if got := d.Sum(nil); !bytes.Equal(got, empty[:]) {
+		t.Errorf("Sum() after Reset = %x, want %x", got, empty)
+	}
+}
+
+func TestPadding(t *testing.T) {
+	for _, n := range []uint64{0, 1, 55, 56, 63, 64, 119, 120} {
+		p := Padding(n)
+		if (n+uint64(len(p)))%BlockSize != 0 {
+			t.Errorf("Padding(%d) length %d does not align to a block", n, len(p))
+		}
+		if p[0] != 0x80 {
+			t.Errorf("Padding(%d) first byte = %#x, want 0x80", n, p[0])
+		}
+	}
+}
+
+func TestNewFromState(t *testing.T) {
+	msg := []byte("secret-prefixed message")
+	suffix := []byte(";admin=true")
+	full := append(append(append([]byte(nil), msg...), Padding(uint64(len(msg)))...), suffix...)
+
+	sum := Sum(msg)
+	d := NewFromState(StateFromSum(sum[:]), uint64(len(msg)+len(Padding(uint64(len(msg))))))
+	d.Write(suffix)
+
+	want := sha1.Sum(full)
+	if got := d.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("extended Sum() = %x, want %x", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+package srp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"sync"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// K is the SRP multiplier parameter; Challenge 36 fixes it to 3.
+var K = big.NewInt(3)
+
+// Challenge is the server's answer to a client hello.
+// U is only set by the simplified protocol of Challenge 38, where the
+// server picks it instead of both sides hashing A||B.
+type Challenge struct {
+	Salt []byte
+	B    *big.Int
+	U    *big.Int
+}
+
+// Transport carries the two round trips of an SRP login. Servers implement
+// it directly, which doubles as an in-memory transport; anything that wants
+// to sit in between (a MITM) just implements it too.
+type Transport interface {
+	Hello(email string, A *big.Int) (*Challenge, error)
+	Login(email string, mac []byte) error
+}
+
+// HashInt hashes the concatenation of parts with SHA-256 and reads the digest as an integer.
+func HashInt(parts ...[]byte) *big.Int {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// SessionKey is K = SHA256(S).
+func SessionKey(s *big.Int) []byte {
+	sum := sha256.Sum256(s.Bytes())
+	return sum[:]
+}
+
+// Proof is the client's login proof, HMAC-SHA256(K, salt).
+func Proof(key, salt []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(salt)
+	return mac.Sum(nil)
+}
+
+// randomExponent returns a random exponent in [1, n).
+func randomExponent(n *big.Int) *big.Int {
+	e, _ := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+	return e.Add(e, big.NewInt(1))
+}
+
+// user is a registered account: salt and verifier v = g^x, never the password.
+type user struct {
+	salt []byte
+	v    *big.Int
+}
+
+// session is a login in progress between Hello and Login.
+type session struct {
+	key  []byte
+	salt []byte
+}
+
+// accounts is the user database and pending-login table shared by both servers.
+type accounts struct {
+	Group *dh.Group
+
+	mu       sync.Mutex
+	users    map[string]user
+	sessions map[string]session
+}
+
+func newAccounts(group *dh.Group) accounts {
+	return accounts{Group: group, users: map[string]user{}, sessions: map[string]session{}}
+}
+
+// Register stores a fresh salt and the password verifier v = g^x for email.
+func (s *accounts) Register(email, password string) {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	x := HashInt(salt, []byte(password))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[email] = user{salt: salt, v: dh.ModExp(s.Group.G, x, s.Group.P)}
+}
+
+// Login checks the client's proof against the pending session.
+func (s *accounts) Login(email string, mac []byte) error {
+	s.mu.Lock()
+	sess, ok := s.sessions[email]
+	delete(s.sessions, email)
+	s.mu.Unlock()
+	if !ok || !hmac.Equal(mac, Proof(sess.key, sess.salt)) {
+		return errors.ErrSRPLoginFailed
+	}
+	return nil
+}
+
+// Server is an SRP-6a server (Challenge 36). It trusts the client's A as-is,
+// which is exactly what Challenge 37 abuses.
+type Server struct {
+	accounts
+}
+
+func NewServer(group *dh.Group) *Server {
+	return &Server{accounts: newAccounts(group)}
+}
+
+// Hello answers with salt and B = kv + g^b, and derives the session key
+// S = (A * v^u)^b with u = SHA256(A||B).
+func (s *Server) Hello(email string, A *big.Int) (*Challenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acct, ok := s.users[email]
+	if !ok {
+		return nil, errors.ErrUnknownUser
+	}
+	N := s.Group.P
+	b := randomExponent(N)
+	B := new(big.Int).Mul(K, acct.v)
+	B.Add(B, dh.ModExp(s.Group.G, b, N)).Mod(B, N)
+
+	u := HashInt(A.Bytes(), B.Bytes())
+	S := new(big.Int).Mul(A, dh.ModExp(acct.v, u, N))
+	S = dh.ModExp(S, b, N)
+	s.sessions[email] = session{key: SessionKey(S), salt: acct.salt}
+	return &Challenge{Salt: acct.salt, B: B}, nil
+}
+
+// SimpleServer implements the simplified SRP of Challenge 38:
+// B = g^b carries no verifier and u is a random 128-bit number.
+type SimpleServer struct {
+	accounts
+}
+
+func NewSimpleServer(group *dh.Group) *SimpleServer {
+	return &SimpleServer{accounts: newAccounts(group)}
+}
+
+// Hello answers with salt, B = g^b and u, and derives S = (A * v^u)^b.
+func (s *SimpleServer) Hello(email string, A *big.Int) (*Challenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acct, ok := s.users[email]
+	if !ok {
+		return nil, errors.ErrUnknownUser
+	}
+	N := s.Group.P
+	b := randomExponent(N)
+	B := dh.ModExp(s.Group.G, b, N)
+	u, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+
+	S := new(big.Int).Mul(A, dh.ModExp(acct.v, u, N))
+	S = dh.ModExp(S, b, N)
+	s.sessions[email] = session{key: SessionKey(S), salt: acct.salt}
+	return &Challenge{Salt: acct.salt, B: B, U: u}, nil
+}
+
+// Client holds the credentials of an SRP user.
+type Client struct {
+	Group    *dh.Group
+	Email    string
+	Password string
+}
+
+// Login runs SRP-6a against t: S = (B - k*g^x)^(a + u*x) with u = SHA256(A||B).
+func (c *Client) Login(t Transport) error {
+	N, g := c.Group.P, c.Group.G
+	a := randomExponent(N)
+	A := dh.ModExp(g, a, N)
+	ch, err := t.Hello(c.Email, A)
+	if err != nil {
+		return err
+	}
+	x := HashInt(ch.Salt, []byte(c.Password))
+	u := HashInt(A.Bytes(), ch.B.Bytes())
+
+	base := new(big.Int).Mul(K, dh.ModExp(g, x, N))
+	base.Sub(ch.B, base).Mod(base, N)
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(exp, a)
+	S := dh.ModExp(base, exp, N)
+	return t.Login(c.Email, Proof(SessionKey(S), ch.Salt))
+}
+
+// LoginSimple runs the simplified protocol against t: S = B^(a + u*x).
+func (c *Client) LoginSimple(t Transport) error {
+	N, g := c.Group.P, c.Group.G
+	a := randomExponent(N)
+	A := dh.ModExp(g, a, N)
+	ch, err := t.Hello(c.Email, A)
+	if err != nil {
+		return err
+	}
+	if ch.U == nil {
+		return errors.ErrUnexpectedMessage
+	}
+	x := HashInt(ch.Salt, []byte(c.Password))
+	exp := new(big.Int).Mul(ch.U, x)
+	exp.Add(exp, a)
+	S := dh.ModExp(ch.B, exp, N)
+	return t.Login(c.Email, Proof(SessionKey(S), ch.Salt))
+}
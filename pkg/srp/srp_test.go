@@ -0,0 +1,74 @@
+package srp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/dh"
+)
+
+func TestServerLogin(t *testing.T) {
+	s := NewServer(dh.NISTGroup())
+	s.Register("alice@example.com", "hunter2")
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  bool
+	}{
+		{name: "correct password", email: "alice@example.com", password: "hunter2"},
+		{name: "wrong password", email: "alice@example.com", password: "hunter3", wantErr: true},
+		{name: "unknown user", email: "bob@example.com", password: "hunter2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{Group: s.Group, Email: tt.email, Password: tt.password}
+			err := c.Login(s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Login() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSimpleServerLogin(t *testing.T) {
+	s := NewSimpleServer(dh.NISTGroup())
+	s.Register("alice@example.com", "hunter2")
+
+	c := &Client{Group: s.Group, Email: "alice@example.com", Password: "hunter2"}
+	if err := c.LoginSimple(s); err != nil {
+		t.Errorf("LoginSimple() error = %v", err)
+	}
+	c.Password = "wrong"
+	if err := c.LoginSimple(s); err == nil {
+		t.Error("LoginSimple() should fail with the wrong password")
+	}
+	// An SRP-6a server never sends u, so the simplified client must refuse it
+	full := NewServer(s.Group)
+	full.Register("alice@example.com", "hunter2")
+	c.Password = "hunter2"
+	if err := c.LoginSimple(full); err == nil {
+		t.Error("LoginSimple() should fail against a server that sends no u")
+	}
+}
+
+func TestLoginWithoutHello(t *testing.T) {
+	s := NewServer(dh.NISTGroup())
+	s.Register("alice@example.com", "hunter2")
+	if err := s.Login("alice@example.com", []byte("proof")); err == nil {
+		t.Error("Login() without a pending session should fail")
+	}
+}
+
+func TestHashInt(t *testing.T) {
+	a := HashInt([]byte("ab"), []byte("c"))
+	b := HashInt([]byte("abc"))
+	if a.Cmp(b) != 0 {
+		t.Error("HashInt() should hash the concatenation of its parts")
+	}
+	if a.Cmp(big.NewInt(0)) <= 0 {
+		t.Error("HashInt() should be positive")
+	}
+}
@@ -0,0 +1,82 @@
+package toymd
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/jonathanlamela/go-cryptopals/pkg/errors"
+)
+
+// BlockSize is the message block size of the toy hash in bytes.
+const BlockSize = 16
+
+// Hash is the deliberately weak Merkle-Damgård hash of Challenges 52-54.
+// Its state is only Bits bits wide (16 to 32), and the compression function
+// encrypts the message block with AES under the state, zero-padded to a
+// 16-byte key, keeping the first Bits bits of the ciphertext.
+// Birthday attacks on it therefore cost around 2^(Bits/2) compressions.
+type Hash struct {
+	Bits int
+	IV   uint32
+
+	// calls counts compression function evaluations
+	calls atomic.Uint64
+}
+
+// New returns a toy hash with a bits-bit state, 16 <= bits <= 32. The IV
+// is a fixed constant truncated to the state size.
+func New(bits int) (*Hash, error) {
+	if bits < 16 || bits > 32 {
+		return nil, errors.ErrInvalidStateSize
+	}
+	h := &Hash{Bits: bits}
+	h.IV = 0xC0FFEE42 & h.mask()
+	return h, nil
+}
+
+func (h *Hash) mask() uint32 {
+	return uint32(1<<h.Bits - 1)
+}
+
+// Calls returns how many times the compression function has run.
+func (h *Hash) Calls() uint64 { return h.calls.Load() }
+
+// Compress runs the compression function over one BlockSize-byte block.
+func (h *Hash) Compress(state uint32, block []byte) uint32 {
+	h.calls.Add(1)
+	var key [16]byte
+	binary.BigEndian.PutUint32(key[:], state<<(32-h.Bits))
+	c, _ := aes.NewCipher(key[:])
+	var out [BlockSize]byte
+	c.Encrypt(out[:], block)
+	return binary.BigEndian.Uint32(out[:]) >> (32 - h.Bits)
+}
+
+// Iterate chains Compress over msg starting from state, without padding.
+// msg must be a whole number of blocks.
+func (h *Hash) Iterate(state uint32, msg []byte) uint32 {
+	for i := 0; i+BlockSize <= len(msg); i += BlockSize {
+		state = h.Compress(state, msg[i:i+BlockSize])
+	}
+	return state
+}
+
+// Sum hashes msg from the IV with MD-strengthening padding.
+func (h *Hash) Sum(msg []byte) uint32 {
+	padded := append(append([]byte(nil), msg...), Padding(uint64(len(msg)))...)
+	return h.Iterate(h.IV, padded)
+}
+
+// Padding returns the MD-strengthening padding for a message of msgLen
+// bytes: 0x80, zeros up to 8 mod 16, then the bit length as a big-endian uint64.
+func Padding(msgLen uint64) []byte {
+	padLen := BlockSize - int((msgLen+8)%BlockSize)
+	if padLen == 0 {
+		padLen = BlockSize
+	}
+	out := make([]byte, padLen+8)
+	out[0] = 0x80
+	binary.BigEndian.PutUint64(out[padLen:], msgLen<<3)
+	return out
+}
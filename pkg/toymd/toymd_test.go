@@ -0,0 +1,56 @@
+package toymd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPadding(t *testing.T) {
+	for _, n := range []uint64{0, 1, 7, 8, 15, 16, 17, 100} {
+		p := Padding(n)
+		if (n+uint64(len(p)))%BlockSize != 0 {
+			t.Errorf("Padding(%d) leaves %d bytes, not a whole block", n, n+uint64(len(p)))
+		}
+		if p[0] != 0x80 || len(p) > BlockSize+8 {
+			t.Errorf("Padding(%d) = %x", n, p)
+		}
+	}
+}
+
+func TestStateSize(t *testing.T) {
+	for _, bits := range []int{16, 17, 20, 24, 31, 32} {
+		h, err := New(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, msg := range []string{"", "YELLOW SUBMARINE", "a somewhat longer message than one block"} {
+			if s := h.Sum([]byte(msg)); s>>bits != 0 && bits < 32 {
+				t.Errorf("New(%d).Sum(%q) = %x exceeds the state size", bits, msg, s)
+			}
+		}
+	}
+}
+
+func TestNewRejectsStateSize(t *testing.T) {
+	for _, bits := range []int{-1, 8, 15, 33, 40} {
+		if _, err := New(bits); err == nil {
+			t.Errorf("New(%d) should fail", bits)
+		}
+	}
+}
+
+func TestIterateMatchesSum(t *testing.T) {
+	h, err := New(24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := bytes.Repeat([]byte("0123456789abcdef"), 3)
+	want := h.Sum(msg)
+	got := h.Iterate(h.Compress(h.IV, msg[:BlockSize]), append(msg[BlockSize:], Padding(uint64(len(msg)))...))
+	if got != want {
+		t.Errorf("chained Compress/Iterate = %x, Sum = %x", got, want)
+	}
+	if h.Calls() == 0 {
+		t.Error("Calls() did not count compressions")
+	}
+}